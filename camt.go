@@ -0,0 +1,350 @@
+package fio
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+	camtDateFormat     = "2006-01-02"
+	camtDateTimeFormat = "2006-01-02T15:04:05"
+
+	camtCredit = "CRDT"
+	camtDebit  = "DBIT"
+
+	// maximal lengths of the camt.053 text fields
+	camtMaxIDLength   = 35
+	camtMaxNameLength = 70
+	camtMaxTextLength = 140
+)
+
+// CAMT053Options represents options passed to WriteCAMT053.
+type CAMT053Options struct {
+	// MessageID identifies the document, it is derived from the account
+	// and transaction ids when empty.
+	MessageID string
+
+	// CreatedAt is the document creation time, current time is used when zero.
+	CreatedAt time.Time
+}
+
+type xmlCAMTDocument struct {
+	XMLName   xml.Name        `xml:"Document"`
+	Namespace string          `xml:"xmlns,attr"`
+	Statement xmlCAMTStmtRoot `xml:"BkToCstmrStmt"`
+}
+
+type xmlCAMTStmtRoot struct {
+	GroupHeader xmlCAMTGroupHeader `xml:"GrpHdr"`
+	Statement   xmlCAMTStatement   `xml:"Stmt"`
+}
+
+type xmlCAMTGroupHeader struct {
+	MessageID string `xml:"MsgId"`
+	CreatedAt string `xml:"CreDtTm"`
+}
+
+type xmlCAMTStatement struct {
+	ID             string         `xml:"Id"`
+	SequenceNumber int64          `xml:"ElctrncSeqNb,omitempty"`
+	CreatedAt      string         `xml:"CreDtTm"`
+	Period         xmlCAMTPeriod  `xml:"FrToDt"`
+	Account        xmlCAMTAccount `xml:"Acct"`
+	Balances       []xmlCAMTBal   `xml:"Bal"`
+	Entries        []xmlCAMTEntry `xml:"Ntry"`
+}
+
+type xmlCAMTPeriod struct {
+	From string `xml:"FrDtTm"`
+	To   string `xml:"ToDtTm"`
+}
+
+type xmlCAMTAccount struct {
+	ID       xmlCAMTAccountID `xml:"Id"`
+	Currency string           `xml:"Ccy,omitempty"`
+	Servicer *xmlCAMTAgent    `xml:"Svcr,omitempty"`
+}
+
+type xmlCAMTAccountID struct {
+	IBAN  string          `xml:"IBAN,omitempty"`
+	Other *xmlCAMTOtherID `xml:"Othr,omitempty"`
+}
+
+type xmlCAMTOtherID struct {
+	ID string `xml:"Id"`
+}
+
+type xmlCAMTAgent struct {
+	BIC   string          `xml:"FinInstnId>BIC,omitempty"`
+	Other *xmlCAMTOtherID `xml:"FinInstnId>Othr,omitempty"`
+}
+
+type xmlCAMTBal struct {
+	Code      string        `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    xmlCAMTAmount `xml:"Amt"`
+	Indicator string        `xml:"CdtDbtInd"`
+	Date      string        `xml:"Dt>Dt"`
+}
+
+type xmlCAMTAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type xmlCAMTEntry struct {
+	Reference       string             `xml:"NtryRef"`
+	Amount          xmlCAMTAmount      `xml:"Amt"`
+	Indicator       string             `xml:"CdtDbtInd"`
+	Status          string             `xml:"Sts"`
+	BookingDate     string             `xml:"BookgDt>Dt"`
+	ValueDate       string             `xml:"ValDt>Dt"`
+	ServicerRef     string             `xml:"AcctSvcrRef"`
+	TransactionCode string             `xml:"BkTxCd>Prtry>Cd"`
+	Details         xmlCAMTTransaction `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo  string             `xml:"AddtlNtryInf,omitempty"`
+}
+
+type xmlCAMTTransaction struct {
+	ServicerRef   string             `xml:"Refs>AcctSvcrRef"`
+	InstructionID string             `xml:"Refs>InstrId,omitempty"`
+	Parties       *xmlCAMTParties    `xml:"RltdPties,omitempty"`
+	Agents        *xmlCAMTAgents     `xml:"RltdAgts,omitempty"`
+	Remittance    *xmlCAMTRemittance `xml:"RmtInf,omitempty"`
+}
+
+type xmlCAMTParties struct {
+	Debtor          *xmlCAMTParty     `xml:"Dbtr,omitempty"`
+	DebtorAccount   *xmlCAMTAccountID `xml:"DbtrAcct>Id,omitempty"`
+	Creditor        *xmlCAMTParty     `xml:"Cdtr,omitempty"`
+	CreditorAccount *xmlCAMTAccountID `xml:"CdtrAcct>Id,omitempty"`
+}
+
+type xmlCAMTParty struct {
+	Name string `xml:"Nm"`
+}
+
+type xmlCAMTAgents struct {
+	Debtor   *xmlCAMTAgent `xml:"DbtrAgt,omitempty"`
+	Creditor *xmlCAMTAgent `xml:"CdtrAgt,omitempty"`
+}
+
+type xmlCAMTRemittance struct {
+	Unstructured []string               `xml:"Ustrd"`
+	Structured   []xmlCAMTStructuredRef `xml:"Strd"`
+}
+
+type xmlCAMTStructuredRef struct {
+	Type      string `xml:"CdtrRefInf>Tp>CdOrPrtry>Prtry"`
+	Reference string `xml:"CdtrRefInf>Ref"`
+}
+
+// WriteCAMT053 writes resp to w as camt.053.001.02 (ISO 20022) bank to customer statement.
+// Credit and debit entries are distinguished by the sign of Transaction.Amount, variable,
+// specific and constant symbols are written as structured creditor references.
+func WriteCAMT053(w io.Writer, resp *TransactionsResponse, opts CAMT053Options) error {
+	info := resp.Info
+	if opts.MessageID == "" {
+		opts.MessageID = fmt.Sprintf("%d-%d-%d", info.AccountID, info.IDFrom, info.IDTo)
+	}
+	if opts.CreatedAt.IsZero() {
+		opts.CreatedAt = time.Now()
+	}
+	createdAt := opts.CreatedAt.Format(camtDateTimeFormat)
+
+	stmt := xmlCAMTStatement{
+		ID:             truncate(opts.MessageID, camtMaxIDLength),
+		SequenceNumber: info.IDList,
+		CreatedAt:      createdAt,
+		Period: xmlCAMTPeriod{
+			From: startOfDay(info.DateStart).Format(camtDateTimeFormat),
+			To:   startOfDay(info.DateEnd).Add(24*time.Hour - time.Second).Format(camtDateTimeFormat),
+		},
+		Account: xmlCAMTAccount{
+			ID:       camtAccountID(info.IBAN, fmt.Sprintf("%d/%v", info.AccountID, info.BankID)),
+			Currency: info.Currency,
+		},
+		Balances: []xmlCAMTBal{
+			camtBalance("OPBD", info.OpeningBalance, info.Currency, info.DateStart),
+			camtBalance("CLBD", info.ClosingBalance, info.Currency, info.DateEnd),
+		},
+	}
+	if info.BIC != "" {
+		stmt.Account.Servicer = &xmlCAMTAgent{BIC: info.BIC}
+	}
+	for _, tx := range resp.Transactions {
+		stmt.Entries = append(stmt.Entries, camtEntry(tx, info.Currency))
+	}
+
+	doc := xmlCAMTDocument{
+		Namespace: camt053Namespace,
+		Statement: xmlCAMTStmtRoot{
+			GroupHeader: xmlCAMTGroupHeader{
+				MessageID: truncate(opts.MessageID, camtMaxIDLength),
+				CreatedAt: createdAt,
+			},
+			Statement: stmt,
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func camtEntry(tx Transaction, currency string) xmlCAMTEntry {
+	if tx.Currency != "" {
+		currency = tx.Currency
+	}
+	id := fmt.Sprint(tx.ID)
+	date := tx.Date.Format(camtDateFormat)
+
+	code := truncate(tx.Type, camtMaxIDLength)
+	if code == "" {
+		code = "NOTPROVIDED"
+	}
+
+	return xmlCAMTEntry{
+		Reference:       id,
+		Amount:          xmlCAMTAmount{Currency: currency, Value: fmtAmount(tx.Amount.Abs())},
+		Indicator:       camtIndicator(tx.Amount),
+		Status:          "BOOK",
+		BookingDate:     date,
+		ValueDate:       date,
+		ServicerRef:     id,
+		TransactionCode: code,
+		Details: xmlCAMTTransaction{
+			ServicerRef:   id,
+			InstructionID: truncate(tx.OrderID, camtMaxIDLength),
+			Parties:       camtParties(tx),
+			Agents:        camtAgents(tx),
+			Remittance:    camtRemittance(tx),
+		},
+		AdditionalInfo: truncate(tx.Comment, 500),
+	}
+}
+
+// camtParties returns the counterparty, it is the debtor of credit and the creditor of debit entries.
+func camtParties(tx Transaction) *xmlCAMTParties {
+	if tx.AccountName == "" && tx.Account == "" {
+		return nil
+	}
+
+	var party *xmlCAMTParty
+	if tx.AccountName != "" {
+		party = &xmlCAMTParty{Name: truncate(tx.AccountName, camtMaxNameLength)}
+	}
+	var account *xmlCAMTAccountID
+	if tx.Account != "" {
+		other := tx.Account
+		if tx.BankCode != "" {
+			other += "/" + tx.BankCode
+		}
+		id := camtAccountID(tx.Account, other)
+		account = &id
+	}
+
+	if tx.Amount.IsNegative() {
+		return &xmlCAMTParties{Creditor: party, CreditorAccount: account}
+	}
+	return &xmlCAMTParties{Debtor: party, DebtorAccount: account}
+}
+
+func camtAgents(tx Transaction) *xmlCAMTAgents {
+	if tx.BIC == "" && tx.BankCode == "" {
+		return nil
+	}
+
+	agent := &xmlCAMTAgent{BIC: tx.BIC}
+	if tx.BankCode != "" {
+		agent.Other = &xmlCAMTOtherID{ID: tx.BankCode}
+	}
+	if tx.Amount.IsNegative() {
+		return &xmlCAMTAgents{Creditor: agent}
+	}
+	return &xmlCAMTAgents{Debtor: agent}
+}
+
+func camtRemittance(tx Transaction) *xmlCAMTRemittance {
+	rmt := new(xmlCAMTRemittance)
+	if tx.RecipientMessage != "" {
+		rmt.Unstructured = append(rmt.Unstructured, truncate(tx.RecipientMessage, camtMaxTextLength))
+	}
+	for _, sym := range []struct{ typ, value string }{
+		{typ: "VS", value: tx.VariableSymbol},
+		{typ: "SS", value: tx.SpecificSymbol},
+		{typ: "KS", value: tx.ConstantSymbol},
+	} {
+		if sym.value != "" {
+			rmt.Structured = append(rmt.Structured, xmlCAMTStructuredRef{Type: sym.typ, Reference: sym.value})
+		}
+	}
+
+	if len(rmt.Unstructured) == 0 && len(rmt.Structured) == 0 {
+		return nil
+	}
+	return rmt
+}
+
+func camtBalance(code string, amount decimal.Decimal, currency string, date time.Time) xmlCAMTBal {
+	return xmlCAMTBal{
+		Code:      code,
+		Amount:    xmlCAMTAmount{Currency: currency, Value: fmtAmount(amount.Abs())},
+		Indicator: camtIndicator(amount),
+		Date:      date.Format(camtDateFormat),
+	}
+}
+
+func camtIndicator(amount decimal.Decimal) string {
+	if amount.IsNegative() {
+		return camtDebit
+	}
+	return camtCredit
+}
+
+// camtAccountID returns account id, account is used as IBAN when it looks like one.
+func camtAccountID(account string, other string) xmlCAMTAccountID {
+	if isIBAN(account) {
+		return xmlCAMTAccountID{IBAN: account}
+	}
+	return xmlCAMTAccountID{Other: &xmlCAMTOtherID{ID: truncate(other, 34)}}
+}
+
+func isIBAN(s string) bool {
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case i < 2 && c >= 'A' && c <= 'Z':
+		case i >= 2 && i < 4 && c >= '0' && c <= '9':
+		case i >= 4 && (c >= '0' && c <= '9' || c >= 'A' && c <= 'Z'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -0,0 +1,153 @@
+package fio
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type testCAMTDocument struct {
+	XMLName   xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+	MessageID string   `xml:"BkToCstmrStmt>GrpHdr>MsgId"`
+	CreatedAt string   `xml:"BkToCstmrStmt>GrpHdr>CreDtTm"`
+	Statement struct {
+		ID       string `xml:"Id"`
+		From     string `xml:"FrToDt>FrDtTm"`
+		To       string `xml:"FrToDt>ToDtTm"`
+		IBAN     string `xml:"Acct>Id>IBAN"`
+		Currency string `xml:"Acct>Ccy"`
+		BIC      string `xml:"Acct>Svcr>FinInstnId>BIC"`
+		Balances []struct {
+			Code      string        `xml:"Tp>CdOrPrtry>Cd"`
+			Amount    xmlCAMTAmount `xml:"Amt"`
+			Indicator string        `xml:"CdtDbtInd"`
+			Date      string        `xml:"Dt>Dt"`
+		} `xml:"Bal"`
+		Entries []struct {
+			Reference       string   `xml:"NtryRef"`
+			Amount          string   `xml:"Amt"`
+			Indicator       string   `xml:"CdtDbtInd"`
+			BookingDate     string   `xml:"BookgDt>Dt"`
+			TransactionCode string   `xml:"BkTxCd>Prtry>Cd"`
+			DebtorName      string   `xml:"NtryDtls>TxDtls>RltdPties>Dbtr>Nm"`
+			CreditorName    string   `xml:"NtryDtls>TxDtls>RltdPties>Cdtr>Nm"`
+			CreditorAccount string   `xml:"NtryDtls>TxDtls>RltdPties>CdtrAcct>Id>Othr>Id"`
+			DebtorIBAN      string   `xml:"NtryDtls>TxDtls>RltdPties>DbtrAcct>Id>IBAN"`
+			Unstructured    []string `xml:"NtryDtls>TxDtls>RmtInf>Ustrd"`
+			Structured      []struct {
+				Type      string `xml:"CdtrRefInf>Tp>CdOrPrtry>Prtry"`
+				Reference string `xml:"CdtrRefInf>Ref"`
+			} `xml:"NtryDtls>TxDtls>RmtInf>Strd"`
+		} `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+func TestWriteCAMT053(t *testing.T) {
+	resp := &TransactionsResponse{
+		Info: StatementInfo{
+			AccountID:      1234562,
+			BankID:         "2010",
+			Currency:       "CZK",
+			IBAN:           "CZ7920100000000001234562",
+			BIC:            "FIOBCZPPXXX",
+			OpeningBalance: decimal.RequireFromString("-10.00"),
+			ClosingBalance: decimal.RequireFromString("20.50"),
+			DateStart:      time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+			DateEnd:        time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+			IDFrom:         1,
+			IDTo:           2,
+		},
+		Transactions: []Transaction{
+			{
+				ID:               1,
+				Date:             time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC),
+				Amount:           decimal.RequireFromString("-20.00"),
+				Account:          "2212-2000000699",
+				AccountName:      "Žluťoučký kůň",
+				BankCode:         "0300",
+				VariableSymbol:   "123",
+				ConstantSymbol:   "0308",
+				RecipientMessage: "nájem",
+				Type:             "Platba převodem uvnitř banky",
+			},
+			{
+				ID:          2,
+				Date:        time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC),
+				Amount:      decimal.RequireFromString("50.50"),
+				Account:     "SK3112000000198742637541",
+				AccountName: "John Doe",
+			},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	err := WriteCAMT053(buf, resp, CAMT053Options{
+		CreatedAt: time.Date(2024, time.March, 1, 8, 30, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	var doc testCAMTDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "1234562-1-2", doc.MessageID)
+	require.Equal(t, "2024-03-01T08:30:00", doc.CreatedAt)
+
+	stmt := doc.Statement
+	require.Equal(t, "1234562-1-2", stmt.ID)
+	require.Equal(t, "2024-02-01T00:00:00", stmt.From)
+	require.Equal(t, "2024-02-29T23:59:59", stmt.To)
+	require.Equal(t, "CZ7920100000000001234562", stmt.IBAN)
+	require.Equal(t, "CZK", stmt.Currency)
+	require.Equal(t, "FIOBCZPPXXX", stmt.BIC)
+
+	require.Len(t, stmt.Balances, 2)
+	require.Equal(t, "OPBD", stmt.Balances[0].Code)
+	require.Equal(t, xmlCAMTAmount{Currency: "CZK", Value: "10.00"}, stmt.Balances[0].Amount)
+	require.Equal(t, "DBIT", stmt.Balances[0].Indicator)
+	require.Equal(t, "2024-02-01", stmt.Balances[0].Date)
+	require.Equal(t, "CLBD", stmt.Balances[1].Code)
+	require.Equal(t, xmlCAMTAmount{Currency: "CZK", Value: "20.50"}, stmt.Balances[1].Amount)
+	require.Equal(t, "CRDT", stmt.Balances[1].Indicator)
+	require.Equal(t, "2024-02-29", stmt.Balances[1].Date)
+
+	require.Len(t, stmt.Entries, 2)
+
+	entry := stmt.Entries[0]
+	require.Equal(t, "1", entry.Reference)
+	require.Equal(t, "20.00", entry.Amount)
+	require.Equal(t, "DBIT", entry.Indicator)
+	require.Equal(t, "2024-02-10", entry.BookingDate)
+	require.Equal(t, "Platba převodem uvnitř banky", entry.TransactionCode)
+	require.Equal(t, "Žluťoučký kůň", entry.CreditorName)
+	require.Equal(t, "2212-2000000699/0300", entry.CreditorAccount)
+	require.Equal(t, []string{"nájem"}, entry.Unstructured)
+	require.Len(t, entry.Structured, 2)
+	require.Equal(t, "VS", entry.Structured[0].Type)
+	require.Equal(t, "123", entry.Structured[0].Reference)
+	require.Equal(t, "KS", entry.Structured[1].Type)
+	require.Equal(t, "0308", entry.Structured[1].Reference)
+
+	entry = stmt.Entries[1]
+	require.Equal(t, "50.50", entry.Amount)
+	require.Equal(t, "CRDT", entry.Indicator)
+	require.Equal(t, "NOTPROVIDED", entry.TransactionCode)
+	require.Equal(t, "John Doe", entry.DebtorName)
+	require.Equal(t, "SK3112000000198742637541", entry.DebtorIBAN)
+	require.Empty(t, entry.Unstructured)
+	require.Empty(t, entry.Structured)
+}
+
+func TestWriteCAMT053MessageID(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := WriteCAMT053(buf, new(TransactionsResponse), CAMT053Options{MessageID: "statement-2024-2"})
+	require.NoError(t, err)
+
+	var doc testCAMTDocument
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "statement-2024-2", doc.MessageID)
+	require.Equal(t, "statement-2024-2", doc.Statement.ID)
+	require.Empty(t, doc.Statement.Entries)
+}
@@ -7,9 +7,13 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"maps"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"time"
 )
 
 const (
@@ -25,11 +29,15 @@ func NewClient(token string, client *http.Client) *Client {
 	}
 
 	c := &Client{
-		BaseURL: baseURL,
-		Token:   token,
-		client:  client,
+		BaseURL:        baseURL,
+		Token:          token,
+		Limiter:        NewRateLimiter(DefaultRateLimitInterval),
+		ResponseFormat: XMLFormat,
+		client:         client,
 	}
 	c.Transactions = &TransactionsService{client: c}
+	c.Payments = &PaymentsService{client: c}
+	c.Merchant = &MerchantService{client: c}
 	return c
 }
 
@@ -40,6 +48,22 @@ type Client struct {
 	Token        string
 	BaseURL      *url.URL
 	Transactions *TransactionsService
+	Payments     *PaymentsService
+	Merchant     *MerchantService
+
+	// Limiter limits the rate of requests made with Token, set to nil
+	// to disable client side rate limiting.
+	Limiter *RateLimiter
+
+	// Retry configures retrying of failed requests, retries are disabled when nil.
+	Retry *RetryPolicy
+
+	// ResponseFormat is the format in which transactions are downloaded
+	// and parsed, XMLFormat (default) and JSONFormat are supported.
+	ResponseFormat ExportFormat
+
+	// ParseOptions configures parsing of the downloaded transactions.
+	ParseOptions ParseOptions
 }
 
 func (c *Client) newGetRequest(ctx context.Context, urlStr string) (*http.Request, error) {
@@ -57,7 +81,64 @@ func (c *Client) newRequest(ctx context.Context, method string, urlStr string, b
 	return http.NewRequestWithContext(ctx, method, urlStr, buf)
 }
 
+func (c *Client) newMultipartRequest(ctx context.Context, urlStr string, fields map[string]string, fileField string, fileName string, r io.Reader) (*http.Request, error) {
+	buf := new(bytes.Buffer)
+	mw := multipart.NewWriter(buf)
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		if err := mw.WriteField(name, fields[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	fw, err := mw.CreateFormFile(fileField, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(fw, r); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlStr, buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req, nil
+}
+
 func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doOnce(req)
+		if err == nil || c.Retry == nil || !c.Retry.shouldRetry(req, err, attempt) {
+			return resp, err
+		}
+
+		if err := sleep(req.Context(), c.Retry.delay(attempt, err, c.rateLimitInterval())); err != nil {
+			return nil, err
+		}
+		if req, err = rewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) rateLimitInterval() time.Duration {
+	if c.Limiter != nil {
+		return c.Limiter.Interval()
+	}
+	return DefaultRateLimitInterval
+}
+
+func (c *Client) doOnce(req *http.Request) (*http.Response, error) {
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(req.Context(), c.Token); err != nil {
+			return nil, err
+		}
+	}
+
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -69,6 +150,26 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 	return resp, nil
 }
 
+func (c *Client) transactionsFile() string {
+	if c.ResponseFormat == JSONFormat {
+		return JSONFormat.fileName()
+	}
+	return XMLFormat.fileName()
+}
+
+func (c *Client) parseTransactions(r io.Reader) (*TransactionsResponse, error) {
+	if c.ResponseFormat == JSONFormat {
+		return parseJSONTransactionsResponse(r, c.ParseOptions)
+	}
+	return parseTransactionsResponse(r, c.ParseOptions)
+}
+
+func (c *Client) buildBaseURL(resource string) string {
+	ref, _ := url.Parse(resource)
+	u := c.BaseURL.ResolveReference(ref)
+	return u.String()
+}
+
 func (c *Client) buildURL(resource string, segments ...string) string {
 	var parts []string
 	parts = append(parts, resource, c.Token)
@@ -84,6 +185,13 @@ type ErrorResponse struct {
 	Response *http.Response
 	Message  string
 	Token    string
+
+	// Err is the error the response status was mapped to, one of ErrRateLimited,
+	// ErrInvalidToken, ErrNotFound, ErrInvalidDate, *ValidationError or *ImportError.
+	Err error
+
+	// Import holds the parsed result of rejected payment orders import.
+	Import *ImportResult
 }
 
 func (r *ErrorResponse) Error() string {
@@ -92,6 +200,11 @@ func (r *ErrorResponse) Error() string {
 		r.Response.StatusCode, r.Message)
 }
 
+// Unwrap returns the error the response status was mapped to.
+func (r *ErrorResponse) Unwrap() error {
+	return r.Err
+}
+
 func (c *Client) checkResponse(r *http.Response) error {
 	if c := r.StatusCode; http.StatusOK <= c && c <= 299 {
 		return nil
@@ -109,18 +222,63 @@ func (c *Client) checkResponse(r *http.Response) error {
 	// 404 resource not found
 	// 400 invalid date format in url
 	// 200 ok
+	switch r.StatusCode {
+	case http.StatusInternalServerError:
+		resp.Err = ErrInvalidToken
+	case http.StatusConflict:
+		resp.Err = ErrRateLimited
+	case http.StatusNotFound:
+		resp.Err = ErrNotFound
+	case http.StatusBadRequest:
+		resp.Err = ErrInvalidDate
+	}
 
-	// try to handle validation error
+	if !strings.Contains(r.Header.Get("Content-Type"), "xml") {
+		return resp
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return resp
+	}
+
+	// try to handle validation error, invalid token comes without xml body
 	if r.StatusCode == http.StatusInternalServerError && strings.Contains(r.Header.Get("Content-Type"), "text/xml") {
 		var errResp xmlErrorResponse
-		if err := xml.NewDecoder(r.Body).Decode(&errResp); err == nil {
+		if err := xml.Unmarshal(body, &errResp); err == nil {
 			resp.Message = errResp.Result.Message
+			resp.Err = &ValidationError{
+				ErrorCode: errResp.Result.ErrorCode,
+				Status:    errResp.Result.Status,
+				Message:   errResp.Result.Message,
+				Detail:    errResp.Result.Detail,
+			}
+			return resp
 		}
 	}
 
+	// try to handle rejected payment orders import, the status code
+	// does not carry the meaning of the other endpoints then
+	var importResp xmlImportResponse
+	if err := xml.Unmarshal(body, &importResp); err == nil {
+		resp.Import = newImportResult(importResp)
+		resp.Message = importErrorMessage(resp.Import)
+		resp.Err = &ImportError{Result: resp.Import}
+	}
+
 	return resp
 }
 
+func importErrorMessage(r *ImportResult) string {
+	for _, d := range r.Failed() {
+		for _, m := range d.Messages {
+			if m.Status == ImportStatusError {
+				return fmt.Sprintf("order %d: %v", d.ID, m.Message)
+			}
+		}
+	}
+	return fmt.Sprintf("import %v (error code %d)", r.Status, r.ErrorCode)
+}
+
 // SanitizeURL redacts the token part of the URL.
 func SanitizeURL(token string, u *url.URL) *url.URL {
 	if token == "" {
@@ -1,6 +1,9 @@
 package fio
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"net/url"
 	"testing"
 
@@ -45,3 +48,88 @@ func TestSanitizeURL(t *testing.T) {
 		})
 	}
 }
+
+const validationErrorResponse = `
+<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<response>
+  <result>
+    <errorCode>11</errorCode>
+    <status>error</status>
+    <message>Chybný formát data.</message>
+    <detail>dateFrom</detail>
+  </result>
+</response>
+`
+
+var checkResponseCases = []struct {
+	name        string
+	status      int
+	contentType string
+	body        string
+	want        error
+}{
+	{
+		name:   "rate limited",
+		status: http.StatusConflict,
+		want:   ErrRateLimited,
+	},
+	{
+		name:   "not found",
+		status: http.StatusNotFound,
+		want:   ErrNotFound,
+	},
+	{
+		name:   "invalid date",
+		status: http.StatusBadRequest,
+		want:   ErrInvalidDate,
+	},
+	{
+		name:   "invalid token",
+		status: http.StatusInternalServerError,
+		want:   ErrInvalidToken,
+	},
+}
+
+func TestCheckResponse(t *testing.T) {
+	for _, c := range checkResponseCases {
+		t.Run(c.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			urlStr := fmt.Sprintf("/v1/rest/set-last-id/%v/1/", testingToken)
+			mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.status)
+			})
+
+			err := client.Transactions.SetLastDownloadID(context.Background(), SetLastDownloadIDOptions{ID: 1})
+
+			var errResp *ErrorResponse
+			require.ErrorAs(t, err, &errResp)
+			require.ErrorIs(t, err, c.want)
+			require.Equal(t, c.status, errResp.Response.StatusCode)
+		})
+	}
+}
+
+func TestCheckResponseValidationError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	urlStr := fmt.Sprintf("/v1/rest/set-last-id/%v/1/", testingToken)
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, validationErrorResponse)
+	})
+
+	err := client.Transactions.SetLastDownloadID(context.Background(), SetLastDownloadIDOptions{ID: 1})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.NotErrorIs(t, err, ErrInvalidToken)
+	require.Equal(t, "11", validationErr.ErrorCode)
+	require.Equal(t, "error", validationErr.Status)
+	require.Equal(t, "Chybný formát data.", validationErr.Message)
+	require.Equal(t, "dateFrom", validationErr.Detail)
+	require.Contains(t, err.Error(), "Chybný formát data.")
+}
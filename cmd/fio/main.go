@@ -0,0 +1,229 @@
+// Command fio downloads transactions and statements using the Fio Banka API.
+//
+// Usage:
+//
+//	fio <command> [flags]
+//
+// The token is read from the FIO_TOKEN environment variable or from the file
+// passed with the -token-file flag. The FIO_BASE_URL environment variable
+// overrides the API base URL.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/jbub/fio"
+)
+
+const usage = `Usage: fio <command> [flags]
+
+Commands:
+  period         print transactions in date period
+  statement      print statement by its year and id
+  last           print transactions since the last download
+  export         export transactions or statement in given format
+  set-last-id    set the last downloaded transaction id
+  set-last-date  set the last download date
+
+Run 'fio <command> -h' to see the command flags.
+`
+
+const dateLayout = "2006-01-02"
+
+type command struct {
+	flags *flag.FlagSet
+	run   func(ctx context.Context, client *fio.Client, w io.Writer) error
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "fio:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		return flag.ErrHelp
+	}
+
+	cmd, err := newCommand(args[0])
+	if err != nil {
+		return err
+	}
+
+	tokenFile := cmd.flags.String("token-file", "", "read token from file instead of FIO_TOKEN")
+	if err := cmd.flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	token, err := readToken(*tokenFile)
+	if err != nil {
+		return err
+	}
+
+	client := fio.NewClient(token, nil)
+	if baseURL := os.Getenv("FIO_BASE_URL"); baseURL != "" {
+		if err := setBaseURL(client, baseURL); err != nil {
+			return err
+		}
+	}
+	return cmd.run(ctx, client, w)
+}
+
+func newCommand(name string) (*command, error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	cmd := &command{flags: flags}
+
+	switch name {
+	case "period":
+		from := dateFlag(flags, "from", "start date of the period (YYYY-MM-DD)")
+		to := dateFlag(flags, "to", "end date of the period (YYYY-MM-DD)")
+		output := flags.String("output", tableOutput, "output format: table, jsonl or csv")
+		cmd.run = func(ctx context.Context, client *fio.Client, w io.Writer) error {
+			opts := fio.ByPeriodOptions{
+				DateFrom: *from,
+				DateTo:   *to,
+			}
+			resp, err := client.Transactions.ByPeriod(ctx, opts)
+			if err != nil {
+				return err
+			}
+			return writeTransactions(w, *output, resp.Transactions)
+		}
+	case "statement":
+		year := flags.Int("year", time.Now().Year(), "year of the statement")
+		id := flags.Int("id", 0, "id of the statement, the last statement is used when zero, "+
+			"which takes two requests and waits for the rate limit between them")
+		output := flags.String("output", tableOutput, "output format: table, jsonl or csv")
+		cmd.run = func(ctx context.Context, client *fio.Client, w io.Writer) error {
+			if *id == 0 && isFlagSet(flags, "year") {
+				return errors.New("-year requires -id, the last statement is used without -id")
+			}
+
+			var (
+				resp *fio.TransactionsResponse
+				err  error
+			)
+			if *id == 0 {
+				resp, err = client.Transactions.GetLastStatement(ctx)
+			} else {
+				resp, err = client.Transactions.GetStatement(ctx, fio.GetStatementOptions{Year: *year, ID: *id})
+			}
+			if err != nil {
+				return err
+			}
+			return writeTransactions(w, *output, resp.Transactions)
+		}
+	case "last":
+		output := flags.String("output", tableOutput, "output format: table, jsonl or csv")
+		cmd.run = func(ctx context.Context, client *fio.Client, w io.Writer) error {
+			resp, err := client.Transactions.SinceLastDownload(ctx)
+			if err != nil {
+				return err
+			}
+			return writeTransactions(w, *output, resp.Transactions)
+		}
+	case "export":
+		from := dateFlag(flags, "from", "start date of the period (YYYY-MM-DD)")
+		to := dateFlag(flags, "to", "end date of the period (YYYY-MM-DD)")
+		year := flags.Int("year", time.Now().Year(), "year of the exported statement")
+		id := flags.Int("id", 0, "id of the exported statement, period is exported when zero")
+		format := flags.String("format", string(fio.XMLFormat), "export format: json, xml, csv, gpc, html, ofx, sta, cba_xml, sba_xml, or pdf and camt053 for statements")
+		cmd.run = func(ctx context.Context, client *fio.Client, w io.Writer) error {
+			if *id != 0 {
+				opts := fio.ExportStatementOptions{
+					Year:   *year,
+					ID:     *id,
+					Format: fio.ExportFormat(*format),
+				}
+				return client.Transactions.ExportStatement(ctx, opts, w)
+			}
+			opts := fio.ExportOptions{
+				DateFrom: *from,
+				DateTo:   *to,
+				Format:   fio.ExportFormat(*format),
+			}
+			return client.Transactions.Export(ctx, opts, w)
+		}
+	case "set-last-id":
+		id := flags.Int("id", 0, "id of the last downloaded transaction (required)")
+		cmd.run = func(ctx context.Context, client *fio.Client, w io.Writer) error {
+			if *id <= 0 {
+				return errors.New("missing -id, the last downloaded transaction id is required")
+			}
+			return client.Transactions.SetLastDownloadID(ctx, fio.SetLastDownloadIDOptions{ID: *id})
+		}
+	case "set-last-date":
+		date := dateFlag(flags, "date", "date of the last download (YYYY-MM-DD)")
+		cmd.run = func(ctx context.Context, client *fio.Client, w io.Writer) error {
+			return client.Transactions.SetLastDownloadDate(ctx, fio.SetLastDownloadDateOptions{Date: *date})
+		}
+	default:
+		return nil, fmt.Errorf(`unknown command: "%v"`, name)
+	}
+	return cmd, nil
+}
+
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	var set bool
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func dateFlag(flags *flag.FlagSet, name string, usage string) *time.Time {
+	t := time.Now()
+	flags.Func(name, usage+" (default today)", func(s string) error {
+		v, err := time.Parse(dateLayout, s)
+		if err != nil {
+			return err
+		}
+		t = v
+		return nil
+	})
+	return &t
+}
+
+func setBaseURL(client *fio.Client, baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return err
+	}
+	client.BaseURL = u
+	return nil
+}
+
+func readToken(tokenFile string) (string, error) {
+	if tokenFile != "" {
+		data, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	token := os.Getenv("FIO_TOKEN")
+	if token == "" {
+		return "", errors.New("missing token, set FIO_TOKEN or use -token-file")
+	}
+	return token, nil
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testingToken = "xxxx"
+
+const transactionsResponse = `
+<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<AccountStatement>
+  <Info>
+    <accountId>2501201133</accountId>
+    <currency>EUR</currency>
+    <openingBalance>0.00</openingBalance>
+    <closingBalance>45.97</closingBalance>
+    <dateStart>2017-04-11+02:00</dateStart>
+    <dateEnd>2017-04-11+02:00</dateEnd>
+  </Info>
+  <TransactionList>
+    <Transaction>
+      <column_22 name="ID pohybu" id="22">13926601410</column_22>
+      <column_0 name="Datum" id="0">2017-04-11+02:00</column_0>
+      <column_1 name="Objem" id="1">45.97</column_1>
+      <column_14 name="Měna" id="14">EUR</column_14>
+      <column_5 name="VS" id="5">0001</column_5>
+      <column_16 name="Zpráva pro příjemce" id="16">john doe, "rent"</column_16>
+    </Transaction>
+  </TransactionList>
+</AccountStatement>
+`
+
+func setupServer(t *testing.T) *http.ServeMux {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	t.Setenv("FIO_TOKEN", testingToken)
+	t.Setenv("FIO_BASE_URL", server.URL)
+	return mux
+}
+
+var periodOutputCases = []struct {
+	output string
+	want   string
+}{
+	{
+		output: "csv",
+		want: "id,date,amount,currency,account,bank_code,account_name,vs,ks,ss,type,message,comment\n" +
+			"13926601410,2017-04-11,45.97,EUR,,,,0001,,,,\"john doe, \"\"rent\"\"\",\n",
+	},
+	{
+		output: "table",
+		want: "id           date        amount  currency  account  bank_code  account_name  vs    ks  ss  type  message           comment\n" +
+			"13926601410  2017-04-11  45.97   EUR                                         0001                john doe, \"rent\"  \n",
+	},
+}
+
+func TestPeriod(t *testing.T) {
+	for _, c := range periodOutputCases {
+		t.Run(c.output, func(t *testing.T) {
+			mux := setupServer(t)
+			urlStr := fmt.Sprintf("/v1/rest/periods/%v/2017-04-01/2017-04-30/transactions.xml", testingToken)
+			mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, transactionsResponse)
+			})
+
+			buf := new(bytes.Buffer)
+			args := []string{"period", "-from", "2017-04-01", "-to", "2017-04-30", "-output", c.output}
+			err := run(context.Background(), args, buf)
+
+			require.NoError(t, err)
+			require.Equal(t, c.want, buf.String())
+		})
+	}
+}
+
+func TestPeriodJSONLines(t *testing.T) {
+	mux := setupServer(t)
+	mux.HandleFunc(fmt.Sprintf("/ib_api/rest/last/%v/transactions.xml", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, transactionsResponse)
+	})
+
+	buf := new(bytes.Buffer)
+	err := run(context.Background(), []string{"last", "-output", "jsonl"}, buf)
+
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), `"ID":13926601410`)
+	require.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")))
+}
+
+func TestStatement(t *testing.T) {
+	for _, c := range periodOutputCases {
+		t.Run(c.output, func(t *testing.T) {
+			mux := setupServer(t)
+			mux.HandleFunc(fmt.Sprintf("/v1/rest/by-id/%v/2017/3/transactions.xml", testingToken), func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, transactionsResponse)
+			})
+
+			buf := new(bytes.Buffer)
+			args := []string{"statement", "-year", "2017", "-id", "3", "-output", c.output}
+			err := run(context.Background(), args, buf)
+
+			require.NoError(t, err)
+			require.Equal(t, c.want, buf.String())
+		})
+	}
+}
+
+func TestStatementLast(t *testing.T) {
+	mux := setupServer(t)
+
+	var lastCalled, statementCalled bool
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/lastStatement/%v/statement", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		lastCalled = true
+		fmt.Fprint(w, "2017,3")
+	})
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/by-id/%v/2017/3/transactions.xml", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		statementCalled = true
+	})
+
+	// the statement is requested after the rate limit interval
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := run(ctx, []string{"statement"}, new(bytes.Buffer))
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, lastCalled)
+	require.False(t, statementCalled)
+}
+
+func TestStatementYearWithoutID(t *testing.T) {
+	mux := setupServer(t)
+
+	var called bool
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	err := run(context.Background(), []string{"statement", "-year", "2017"}, new(bytes.Buffer))
+
+	require.EqualError(t, err, "-year requires -id, the last statement is used without -id")
+	require.False(t, called)
+}
+
+func TestLast(t *testing.T) {
+	for _, c := range periodOutputCases {
+		t.Run(c.output, func(t *testing.T) {
+			mux := setupServer(t)
+			mux.HandleFunc(fmt.Sprintf("/ib_api/rest/last/%v/transactions.xml", testingToken), func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, transactionsResponse)
+			})
+
+			buf := new(bytes.Buffer)
+			err := run(context.Background(), []string{"last", "-output", c.output}, buf)
+
+			require.NoError(t, err)
+			require.Equal(t, c.want, buf.String())
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	mux := setupServer(t)
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/periods/%v/2017-04-01/2017-04-30/transactions.gpc", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "gpc data")
+	})
+
+	buf := new(bytes.Buffer)
+	args := []string{"export", "-from", "2017-04-01", "-to", "2017-04-30", "-format", "gpc"}
+	err := run(context.Background(), args, buf)
+
+	require.NoError(t, err)
+	require.Equal(t, "gpc data", buf.String())
+}
+
+func TestExportStatement(t *testing.T) {
+	mux := setupServer(t)
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/by-id/%v/2017/3/transactions.pdf", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "pdf data")
+	})
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/by-id/%v/%v/4/transactions.pdf", testingToken, time.Now().Year()), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "current year")
+	})
+
+	buf := new(bytes.Buffer)
+	err := run(context.Background(), []string{"export", "-year", "2017", "-id", "3", "-format", "pdf"}, buf)
+	require.NoError(t, err)
+	require.Equal(t, "pdf data", buf.String())
+
+	buf.Reset()
+	err = run(context.Background(), []string{"export", "-id", "4", "-format", "pdf"}, buf)
+	require.NoError(t, err)
+	require.Equal(t, "current year", buf.String())
+}
+
+func TestSetLastID(t *testing.T) {
+	mux := setupServer(t)
+
+	var called bool
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/set-last-id/%v/42/", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	err := run(context.Background(), []string{"set-last-id", "-id", "42"}, new(bytes.Buffer))
+
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+func TestSetLastIDMissing(t *testing.T) {
+	mux := setupServer(t)
+
+	var called bool
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/set-last-id/%v/", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	err := run(context.Background(), []string{"set-last-id"}, new(bytes.Buffer))
+
+	require.EqualError(t, err, "missing -id, the last downloaded transaction id is required")
+	require.False(t, called)
+}
+
+func TestSetLastDate(t *testing.T) {
+	mux := setupServer(t)
+
+	var called bool
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/set-last-date/%v/2017-04-11/", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	err := run(context.Background(), []string{"set-last-date", "-date", "2017-04-11"}, new(bytes.Buffer))
+
+	require.NoError(t, err)
+	require.True(t, called)
+}
+
+func TestUnknownCommand(t *testing.T) {
+	err := run(context.Background(), []string{"unknown"}, new(bytes.Buffer))
+
+	require.EqualError(t, err, `unknown command: "unknown"`)
+}
+
+func TestMissingToken(t *testing.T) {
+	t.Setenv("FIO_TOKEN", "")
+
+	err := run(context.Background(), []string{"last"}, new(bytes.Buffer))
+
+	require.EqualError(t, err, "missing token, set FIO_TOKEN or use -token-file")
+}
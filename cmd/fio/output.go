@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jbub/fio"
+)
+
+const (
+	tableOutput = "table"
+	jsonlOutput = "jsonl"
+	csvOutput   = "csv"
+)
+
+var transactionHeader = []string{
+	"id", "date", "amount", "currency", "account", "bank_code", "account_name",
+	"vs", "ks", "ss", "type", "message", "comment",
+}
+
+func transactionRecord(tx fio.Transaction) []string {
+	return []string{
+		strconv.FormatInt(tx.ID, 10),
+		tx.Date.Format(dateLayout),
+		tx.Amount.String(),
+		tx.Currency,
+		tx.Account,
+		tx.BankCode,
+		tx.AccountName,
+		tx.VariableSymbol,
+		tx.ConstantSymbol,
+		tx.SpecificSymbol,
+		tx.Type,
+		tx.RecipientMessage,
+		tx.Comment,
+	}
+}
+
+func writeTransactions(w io.Writer, output string, txs []fio.Transaction) error {
+	switch output {
+	case tableOutput:
+		return writeTable(w, txs)
+	case jsonlOutput:
+		return writeJSONLines(w, txs)
+	case csvOutput:
+		return writeCSV(w, txs)
+	default:
+		return fmt.Errorf(`unknown output format: "%v"`, output)
+	}
+}
+
+func writeTable(w io.Writer, txs []fio.Transaction) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(transactionHeader, "\t"))
+	for _, tx := range txs {
+		fmt.Fprintln(tw, strings.Join(transactionRecord(tx), "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeJSONLines(w io.Writer, txs []fio.Transaction) error {
+	enc := json.NewEncoder(w)
+	for _, tx := range txs {
+		if err := enc.Encode(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCSV(w io.Writer, txs []fio.Transaction) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(transactionHeader); err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		if err := cw.Write(transactionRecord(tx)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,223 @@
+package fio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const csvDateFormat = "02.01.2006"
+
+// csvInfoFields maps keys of the CSV header block to StatementInfo fields.
+var csvInfoFields = map[string]func(info *StatementInfo, v string) error{
+	"accountId": func(info *StatementInfo, v string) (err error) {
+		info.AccountID, err = parseOptionalInteger(v)
+		return err
+	},
+	"bankId": func(info *StatementInfo, v string) error {
+		info.BankID = v
+		return nil
+	},
+	"currency": func(info *StatementInfo, v string) error {
+		info.Currency = v
+		return nil
+	},
+	"iban": func(info *StatementInfo, v string) error {
+		info.IBAN = v
+		return nil
+	},
+	"bic": func(info *StatementInfo, v string) error {
+		info.BIC = v
+		return nil
+	},
+	"openingBalance": func(info *StatementInfo, v string) (err error) {
+		info.OpeningBalance, err = parseCSVAmount(v)
+		return err
+	},
+	"closingBalance": func(info *StatementInfo, v string) (err error) {
+		info.ClosingBalance, err = parseCSVAmount(v)
+		return err
+	},
+	"dateStart": func(info *StatementInfo, v string) (err error) {
+		info.DateStart, err = parseCSVDate(v)
+		return err
+	},
+	"dateEnd": func(info *StatementInfo, v string) (err error) {
+		info.DateEnd, err = parseCSVDate(v)
+		return err
+	},
+	"yearList": func(info *StatementInfo, v string) (err error) {
+		info.YearList, err = parseOptionalInteger(v)
+		return err
+	},
+	"idList": func(info *StatementInfo, v string) (err error) {
+		info.IDList, err = parseOptionalInteger(v)
+		return err
+	},
+	"idFrom": func(info *StatementInfo, v string) (err error) {
+		info.IDFrom, err = parseOptionalInteger(v)
+		return err
+	},
+	"idTo": func(info *StatementInfo, v string) (err error) {
+		info.IDTo, err = parseOptionalInteger(v)
+		return err
+	},
+	"idLastDownload": func(info *StatementInfo, v string) (err error) {
+		info.IDLastDownload, err = parseOptionalInteger(v)
+		return err
+	},
+}
+
+// csvInfoAliases maps Czech keys of the CSV header block to the English ones.
+var csvInfoAliases = map[string]string{
+	"Číslo účtu":            "accountId",
+	"Kód banky":             "bankId",
+	"Měna":                  "currency",
+	"IBAN":                  "iban",
+	"BIC":                   "bic",
+	"Počáteční stav":        "openingBalance",
+	"Koncový stav":          "closingBalance",
+	"Datum od":              "dateStart",
+	"Datum do":              "dateEnd",
+	"Rok výpisu":            "yearList",
+	"Číslo výpisu":          "idList",
+	"ID pohybu od":          "idFrom",
+	"ID pohybu do":          "idTo",
+	"ID posledního stažení": "idLastDownload",
+}
+
+// csvColumnIDs maps localized column names to column ids of transactionColumns.
+var csvColumnIDs = func() map[string]string {
+	ids := make(map[string]string, len(transactionColumns))
+	for id, spec := range transactionColumns {
+		ids[spec.name] = id
+	}
+	return ids
+}()
+
+// ParseCSV parses statement exported in CSVFormat. The export starts with header
+// block of statement info followed by semicolon separated transactions with localized
+// column names, amounts use decimal comma and dates are in dd.mm.yyyy format.
+// Unknown columns are handled according to opts as in the other formats.
+func ParseCSV(r io.Reader, opts ParseOptions) (*TransactionsResponse, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\ufeff" {
+		if _, err := br.Discard(len(bom)); err != nil {
+			return nil, err
+		}
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = ';'
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	resp := new(TransactionsResponse)
+	var (
+		hasInfo bool
+		header  []string
+	)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			if key, ok := csvInfoKey(record); ok {
+				if err := csvInfoFields[key](&resp.Info, strings.TrimSpace(record[1])); err != nil {
+					return nil, fmt.Errorf(`unable to parse statement info: "%v": %w`, record[0], err)
+				}
+				hasInfo = true
+				continue
+			}
+			header = record
+			continue
+		}
+
+		tx, err := parseCSVTransaction(header, record, opts)
+		if err != nil {
+			return nil, err
+		}
+		resp.Transactions = append(resp.Transactions, *tx)
+	}
+	if !hasInfo {
+		return nil, errors.New("missing statement info")
+	}
+	return resp, nil
+}
+
+// csvInfoKey returns key of the header block record, the block ends with the column names.
+func csvInfoKey(record []string) (string, bool) {
+	if len(record) != 2 {
+		return "", false
+	}
+	key := strings.TrimSpace(record[0])
+	if alias, ok := csvInfoAliases[key]; ok {
+		key = alias
+	}
+	_, ok := csvInfoFields[key]
+	return key, ok
+}
+
+func parseCSVTransaction(header []string, record []string, opts ParseOptions) (*Transaction, error) {
+	if len(record) != len(header) {
+		return nil, fmt.Errorf("unexpected number of columns: %d", len(record))
+	}
+
+	cols := make([]Column, 0, len(record))
+	for i, v := range record {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		name := strings.TrimSpace(header[i])
+		id, ok := csvColumnIDs[name]
+		if !ok {
+			id = name
+		}
+		v, err := csvColumnValue(id, v)
+		if err != nil {
+			return nil, fmt.Errorf(`unable to parse column: "%v": %w`, name, err)
+		}
+		cols = append(cols, Column{ID: id, Name: name, Value: v})
+	}
+	return parseTransaction(cols, opts)
+}
+
+// csvColumnValue converts localized value to the format of the other exports.
+func csvColumnValue(id string, v string) (string, error) {
+	switch id {
+	case fieldDate:
+		t, err := parseCSVDate(v)
+		if err != nil {
+			return "", err
+		}
+		return t.Format(jsonTimeFormat), nil
+	case fieldAmount:
+		return normalizeCSVAmount(v), nil
+	default:
+		return v, nil
+	}
+}
+
+func parseCSVAmount(s string) (decimal.Decimal, error) {
+	return parseAmount(normalizeCSVAmount(s))
+}
+
+func normalizeCSVAmount(s string) string {
+	return strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(s)
+}
+
+func parseCSVDate(s string) (time.Time, error) {
+	return time.ParseInLocation(csvDateFormat, s, xmlGMTLocation)
+}
@@ -0,0 +1,152 @@
+package fio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const csvStatement = "\ufeff\"accountId\";\"1234562\"\r\n" +
+	"\"bankId\";\"2010\"\r\n" +
+	"\"currency\";\"CZK\"\r\n" +
+	"\"iban\";\"CZ7920100000000001234562\"\r\n" +
+	"\"bic\";\"FIOBCZPPXXX\"\r\n" +
+	"\"openingBalance\";\"1 100,50\"\r\n" +
+	"\"closingBalance\";\"1 080,50\"\r\n" +
+	"\"dateStart\";\"01.02.2024\"\r\n" +
+	"\"dateEnd\";\"29.02.2024\"\r\n" +
+	"\"idFrom\";\"26000000001\"\r\n" +
+	"\"idTo\";\"26000000001\"\r\n" +
+	"\r\n" +
+	"\"ID pohybu\";\"Datum\";\"Objem\";\"Měna\";\"Protiúčet\";\"Název protiúčtu\";\"Kód banky\";\"KS\";\"VS\";\"SS\";\"Zpráva pro příjemce\";\"Typ\"\r\n" +
+	"\"26000000001\";\"10.02.2024\";\"-20,00\";\"CZK\";\"2212-2000000699\";\"Žluťoučký kůň\";\"0300\";\"0308\";\"123\";\"\";\"nájem; únor\";\"Platba převodem uvnitř banky\"\r\n"
+
+func TestParseCSV(t *testing.T) {
+	resp, err := ParseCSV(strings.NewReader(csvStatement), ParseOptions{})
+	require.NoError(t, err)
+
+	info := resp.Info
+	require.Equal(t, int64(1234562), info.AccountID)
+	require.Equal(t, "2010", info.BankID)
+	require.Equal(t, "CZK", info.Currency)
+	require.Equal(t, "CZ7920100000000001234562", info.IBAN)
+	require.Equal(t, "FIOBCZPPXXX", info.BIC)
+	require.Equal(t, "1100.50", info.OpeningBalance.StringFixed(2))
+	require.Equal(t, "1080.50", info.ClosingBalance.StringFixed(2))
+	require.Equal(t, "2024-02-01", info.DateStart.Format("2006-01-02"))
+	require.Equal(t, "2024-02-29", info.DateEnd.Format("2006-01-02"))
+	require.Equal(t, int64(26000000001), info.IDFrom)
+	require.Equal(t, int64(26000000001), info.IDTo)
+
+	require.Len(t, resp.Transactions, 1)
+	tx := resp.Transactions[0]
+	require.Equal(t, int64(26000000001), tx.ID)
+	require.Equal(t, "2024-02-10", tx.Date.Format("2006-01-02"))
+	require.Equal(t, "-20.00", tx.Amount.StringFixed(2))
+	require.Equal(t, "CZK", tx.Currency)
+	require.Equal(t, "2212-2000000699", tx.Account)
+	require.Equal(t, "Žluťoučký kůň", tx.AccountName)
+	require.Equal(t, "0300", tx.BankCode)
+	require.Equal(t, "0308", tx.ConstantSymbol)
+	require.Equal(t, "123", tx.VariableSymbol)
+	require.Equal(t, "", tx.SpecificSymbol)
+	require.Equal(t, "nájem; únor", tx.RecipientMessage)
+	require.Equal(t, "Platba převodem uvnitř banky", tx.Type)
+}
+
+// csvExportHeader is the transaction header of the CSV export with all the columns
+// in the order documented by the API, it is written out literally on purpose.
+const csvExportHeader = "\"ID pohybu\";\"Datum\";\"Objem\";\"Měna\";\"Protiúčet\";\"Název protiúčtu\";\"Kód banky\";\"Název banky\";" +
+	"\"KS\";\"VS\";\"SS\";\"Uživatelská identifikace\";\"Zpráva pro příjemce\";\"Typ\";\"Provedl\";\"Upřesnění\";" +
+	"\"Komentář\";\"BIC\";\"ID pokynu\";\"Reference plátce\"\r\n"
+
+func TestParseCSVExportHeader(t *testing.T) {
+	data := "\"accountId\";\"1234562\"\r\n" +
+		"\r\n" +
+		csvExportHeader +
+		"\"26000000002\";\"11.02.2024\";\"1 500,00\";\"CZK\";\"2212-2000000699\";\"Jan Novák\";\"0300\";\"ČSOB, a.s.\";" +
+		"\"0308\";\"456\";\"789\";\"Novák\";\"faktura\";\"Bezhotovostní příjem\";\"Novák, Jan\";\"1500,00 CZK\";" +
+		"\"poznámka\";\"CEKOCZPP\";\"12345\";\"REF-1\"\r\n"
+
+	resp, err := ParseCSV(strings.NewReader(data), ParseOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Transactions, 1)
+	tx := resp.Transactions[0]
+	require.Equal(t, int64(26000000002), tx.ID)
+	require.Equal(t, "2024-02-11", tx.Date.Format("2006-01-02"))
+	require.Equal(t, "1500.00", tx.Amount.StringFixed(2))
+	require.Equal(t, "CZK", tx.Currency)
+	require.Equal(t, "2212-2000000699", tx.Account)
+	require.Equal(t, "Jan Novák", tx.AccountName)
+	require.Equal(t, "0300", tx.BankCode)
+	require.Equal(t, "ČSOB, a.s.", tx.BankName)
+	require.Equal(t, "0308", tx.ConstantSymbol)
+	require.Equal(t, "456", tx.VariableSymbol)
+	require.Equal(t, "789", tx.SpecificSymbol)
+	require.Equal(t, "Novák", tx.UserIdentification)
+	require.Equal(t, "faktura", tx.RecipientMessage)
+	require.Equal(t, "Bezhotovostní příjem", tx.Type)
+	require.Equal(t, "Novák, Jan", tx.Author)
+	require.Equal(t, "1500,00 CZK", tx.Specification)
+	require.Equal(t, "poznámka", tx.Comment)
+	require.Equal(t, "CEKOCZPP", tx.BIC)
+	require.Equal(t, "12345", tx.OrderID)
+	require.Equal(t, "REF-1", tx.PayerReference)
+	require.Empty(t, tx.Extra)
+}
+
+func TestParseCSVLenient(t *testing.T) {
+	data := strings.Replace(csvStatement, "\"Typ\"", "\"Neznámý\"", 1)
+
+	var unknown []Column
+	resp, err := ParseCSV(strings.NewReader(data), ParseOptions{
+		Lenient: true,
+		OnUnknownColumn: func(col Column) {
+			unknown = append(unknown, col)
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Transactions, 1)
+	tx := resp.Transactions[0]
+	require.Empty(t, tx.Type)
+	require.Equal(t, Column{ID: "Neznámý", Name: "Neznámý", Value: "Platba převodem uvnitř banky"}, tx.Extra["Neznámý"])
+	require.Equal(t, []Column{tx.Extra["Neznámý"]}, unknown)
+}
+
+func TestParseCSVCzechHeader(t *testing.T) {
+	data := "\"Číslo účtu\";\"1234562\"\r\n" +
+		"\"Počáteční stav\";\"10,00\"\r\n" +
+		"\"Datum od\";\"01.02.2024\"\r\n" +
+		"\r\n" +
+		"\"ID pohybu\";\"Datum\";\"Objem\"\r\n"
+
+	resp, err := ParseCSV(strings.NewReader(data), ParseOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1234562), resp.Info.AccountID)
+	require.Equal(t, "10.00", resp.Info.OpeningBalance.StringFixed(2))
+	require.Equal(t, "2024-02-01", resp.Info.DateStart.Format("2006-01-02"))
+	require.Empty(t, resp.Transactions)
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{name: "missing info", data: "\"ID pohybu\";\"Datum\"\r\n\"1\";\"10.02.2024\"\r\n"},
+		{name: "invalid balance", data: strings.Replace(csvStatement, "1 100,50", "abc", 1)},
+		{name: "invalid date", data: strings.Replace(csvStatement, "10.02.2024", "2024-02-10", 1)},
+		{name: "unknown column", data: strings.Replace(csvStatement, "\"Typ\"", "\"Neznámý\"", 1)},
+		{name: "missing column", data: strings.Replace(csvStatement, ";\"Platba převodem uvnitř banky\"", "", 1)},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			_, err := ParseCSV(strings.NewReader(cs.data), ParseOptions{})
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package fio
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors the fio API response statuses are mapped to, use errors.Is to check
+// whether the error returned by the client is one of them.
+var (
+	ErrRateLimited  = errors.New("rate limit exceeded")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrNotFound     = errors.New("resource not found")
+	ErrInvalidDate  = errors.New("invalid date format")
+)
+
+// ErrUnsupportedFormat is returned when export format is not available for the endpoint.
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// ValidationError represents validation error returned by the fio API,
+// use errors.As to get it from the error returned by the client.
+type ValidationError struct {
+	ErrorCode string
+	Status    string
+	Message   string
+	Detail    string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("validation error %v: %v", e.ErrorCode, e.Message)
+	}
+	return fmt.Sprintf("validation error %v: %v (%v)", e.ErrorCode, e.Message, e.Detail)
+}
+
+// ImportError represents payment orders import rejected by the fio API,
+// use errors.As to get it from the error returned by the client.
+type ImportError struct {
+	Result *ImportResult
+}
+
+func (e *ImportError) Error() string {
+	return "import rejected: " + importErrorMessage(e.Result)
+}
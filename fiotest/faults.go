@@ -0,0 +1,169 @@
+package fiotest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+)
+
+// Route identifies API endpoint served by the server.
+type Route string
+
+// Routes served by the server.
+const (
+	AnyRoute           Route = ""
+	PeriodsRoute       Route = "periods"
+	ByIDRoute          Route = "by-id"
+	LastStatementRoute Route = "lastStatement"
+	LastRoute          Route = "last"
+	SetLastIDRoute     Route = "set-last-id"
+	SetLastDateRoute   Route = "set-last-date"
+	ImportRoute        Route = "import"
+)
+
+// Fault represents failure injected into responses of the server.
+type Fault struct {
+	// Route the fault applies to, AnyRoute matches all the routes.
+	Route Route
+
+	// Times is the number of requests the fault applies to, zero means all the requests.
+	Times int
+
+	// Delay postpones the response.
+	Delay time.Duration
+
+	// StatusCode replaces the response with an empty one with this status,
+	// the request is not passed to the server and its state is unchanged.
+	StatusCode int
+
+	// ErrorCode, Message and Detail set the body of the StatusCode response
+	// to XML validation error, which is what the API returns with status 500.
+	ErrorCode string
+	Message   string
+	Detail    string
+
+	// Truncate cuts the response body to the given number of bytes.
+	Truncate int
+
+	// ContentType overrides Content-Type of the response.
+	ContentType string
+}
+
+// InjectFault adds fault to the server. When more faults match the request
+// the one added first is used.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all the injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// fault returns the fault matching r and consumes one of its Times.
+func (s *Server) fault(r *http.Request) (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	route := requestRoute(r)
+	for i, f := range s.faults {
+		if f.Route != AnyRoute && f.Route != route {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return *f, true
+	}
+	return Fault{}, false
+}
+
+// requestRoute returns route of r, paths are in form /v1/rest/{route}/...
+func requestRoute(r *http.Request) Route {
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) < 4 {
+		return AnyRoute
+	}
+	return Route(parts[3])
+}
+
+func (s *Server) serveFault(w http.ResponseWriter, r *http.Request, f Fault) {
+	if f.Delay > 0 {
+		t := time.NewTimer(f.Delay)
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			t.Stop()
+			return
+		}
+	}
+
+	if f.StatusCode != 0 {
+		writeFault(w, f)
+		return
+	}
+	if f.Truncate == 0 && f.ContentType == "" {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, r)
+
+	body := rec.Body.Bytes()
+	if f.Truncate > 0 && f.Truncate < len(body) {
+		body = body[:f.Truncate]
+	}
+	for k, v := range rec.Header() {
+		w.Header()[k] = v
+	}
+	if f.ContentType != "" {
+		w.Header().Set("Content-Type", f.ContentType)
+	}
+	w.WriteHeader(rec.Code)
+	_, _ = w.Write(body)
+}
+
+func writeFault(w http.ResponseWriter, f Fault) {
+	if f.ErrorCode == "" && f.Message == "" {
+		if f.ContentType != "" {
+			w.Header().Set("Content-Type", f.ContentType)
+		}
+		w.WriteHeader(f.StatusCode)
+		return
+	}
+
+	buf := new(strings.Builder)
+	buf.WriteString(xml.Header)
+	buf.WriteString("<response>\n  <result>\n")
+	writeXMLElement(buf, "errorCode", f.ErrorCode)
+	writeXMLElement(buf, "status", "error")
+	writeXMLElement(buf, "message", f.Message)
+	writeXMLElement(buf, "detail", f.Detail)
+	buf.WriteString("  </result>\n</response>\n")
+
+	body := buf.String()
+	if f.Truncate > 0 && f.Truncate < len(body) {
+		body = body[:f.Truncate]
+	}
+
+	contentType := "text/xml;charset=UTF-8"
+	if f.ContentType != "" {
+		contentType = f.ContentType
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(f.StatusCode)
+	fmt.Fprint(w, body)
+}
@@ -0,0 +1,132 @@
+package fiotest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbub/fio"
+)
+
+func TestFaultRateLimit(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{Route: LastRoute, StatusCode: http.StatusConflict, Times: 1})
+	client := srv.Client(testingToken)
+	client.Retry = nil
+	ctx := context.Background()
+
+	_, err := client.Transactions.SinceLastDownload(ctx)
+	assert.True(t, errors.Is(err, fio.ErrRateLimited))
+	assert.Equal(t, int64(0), srv.LastDownloadID(testingToken))
+
+	resp, err := client.Transactions.SinceLastDownload(ctx)
+	require.NoError(t, err)
+	assert.Len(t, resp.Transactions, 3)
+}
+
+func TestFaultRetried(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{Route: PeriodsRoute, StatusCode: http.StatusConflict, Times: 2})
+	client := srv.Client(testingToken)
+	client.Retry = &fio.RetryPolicy{MaxAttempts: 3, Backoff: []time.Duration{time.Millisecond}}
+
+	resp, err := client.Transactions.ByPeriod(context.Background(), fio.ByPeriodOptions{
+		DateFrom: date(2024, 1, 1),
+		DateTo:   date(2024, 12, 31),
+	})
+	require.NoError(t, err)
+	assert.Len(t, resp.Transactions, 3)
+}
+
+func TestFaultValidationError(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{
+		Route:      SetLastIDRoute,
+		StatusCode: http.StatusInternalServerError,
+		ErrorCode:  "21",
+		Message:    "Neplatné ID pohybu.",
+		Detail:     "id",
+	})
+	client := srv.Client(testingToken)
+
+	err := client.Transactions.SetLastDownloadID(context.Background(), fio.SetLastDownloadIDOptions{ID: 1})
+
+	var validationErr *fio.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "21", validationErr.ErrorCode)
+	assert.Equal(t, "error", validationErr.Status)
+	assert.Equal(t, "Neplatné ID pohybu.", validationErr.Message)
+	assert.Equal(t, "id", validationErr.Detail)
+}
+
+func TestFaultInvalidToken(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{StatusCode: http.StatusInternalServerError})
+	client := srv.Client(testingToken)
+
+	_, err := client.Transactions.LastStatement(context.Background())
+	assert.True(t, errors.Is(err, fio.ErrInvalidToken))
+}
+
+func TestFaultNotFound(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{Route: ByIDRoute, StatusCode: http.StatusNotFound})
+	client := srv.Client(testingToken)
+
+	_, err := client.Transactions.GetStatement(context.Background(), fio.GetStatementOptions{Year: 2024, ID: 1})
+	assert.True(t, errors.Is(err, fio.ErrNotFound))
+}
+
+func TestFaultTruncated(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{Route: PeriodsRoute, Truncate: 200})
+	client := srv.Client(testingToken)
+
+	_, err := client.Transactions.ByPeriod(context.Background(), fio.ByPeriodOptions{
+		DateFrom: date(2024, 1, 1),
+		DateTo:   date(2024, 12, 31),
+	})
+	require.Error(t, err)
+}
+
+func TestFaultSlowResponse(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{Route: LastRoute, Delay: time.Second})
+	client := srv.Client(testingToken)
+	client.Retry = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Transactions.SinceLastDownload(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, int64(0), srv.LastDownloadID(testingToken))
+}
+
+func TestFaultContentType(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{Route: ImportRoute, StatusCode: http.StatusInternalServerError, ContentType: "text/html", Message: "boom"})
+	client := srv.Client(testingToken)
+
+	_, err := client.Payments.ImportFile(context.Background(), fio.XMLImportFormat, strings.NewReader("<Import/>"))
+
+	var validationErr *fio.ValidationError
+	assert.False(t, errors.As(err, &validationErr))
+	assert.True(t, errors.Is(err, fio.ErrInvalidToken))
+}
+
+func TestClearFaults(t *testing.T) {
+	srv := newTestServer(t)
+	srv.InjectFault(Fault{StatusCode: http.StatusNotFound})
+	srv.ClearFaults()
+	client := srv.Client(testingToken)
+
+	_, err := client.Transactions.SinceLastDownload(context.Background())
+	require.NoError(t, err)
+}
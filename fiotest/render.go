@@ -0,0 +1,551 @@
+package fiotest
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jbub/fio"
+	"github.com/jbub/fio/internal/gpc"
+)
+
+const (
+	xmlTimeFormat   = "2006-01-02-07:00"
+	jsonTimeFormat  = "2006-01-02-0700"
+	csvDateFormat   = "02.01.2006"
+	gpcDateFormat   = "020106"
+	ofxDateFormat   = "20060102"
+	mt940DateFormat = "060102"
+)
+
+// column describes statement column rendered by the server.
+type column struct {
+	id     int
+	name   string
+	number bool
+	value  func(tx fio.Transaction) string
+}
+
+var columns = []column{
+	{id: 22, name: "ID pohybu", number: true, value: func(tx fio.Transaction) string { return strconv.FormatInt(tx.ID, 10) }},
+	{id: 0, name: "Datum", value: func(tx fio.Transaction) string { return tx.Date.Format(xmlTimeFormat) }},
+	{id: 1, name: "Objem", number: true, value: func(tx fio.Transaction) string { return fmtAmount(tx.Amount) }},
+	{id: 14, name: "Měna", value: func(tx fio.Transaction) string { return tx.Currency }},
+	{id: 2, name: "Protiúčet", value: func(tx fio.Transaction) string { return tx.Account }},
+	{id: 10, name: "Název protiúčtu", value: func(tx fio.Transaction) string { return tx.AccountName }},
+	{id: 3, name: "Kód banky", value: func(tx fio.Transaction) string { return tx.BankCode }},
+	{id: 12, name: "Název banky", value: func(tx fio.Transaction) string { return tx.BankName }},
+	{id: 4, name: "KS", value: func(tx fio.Transaction) string { return tx.ConstantSymbol }},
+	{id: 5, name: "VS", value: func(tx fio.Transaction) string { return tx.VariableSymbol }},
+	{id: 6, name: "SS", value: func(tx fio.Transaction) string { return tx.SpecificSymbol }},
+	{id: 7, name: "Uživatelská identifikace", value: func(tx fio.Transaction) string { return tx.UserIdentification }},
+	{id: 16, name: "Zpráva pro příjemce", value: func(tx fio.Transaction) string { return tx.RecipientMessage }},
+	{id: 8, name: "Typ", value: func(tx fio.Transaction) string { return tx.Type }},
+	{id: 9, name: "Provedl", value: func(tx fio.Transaction) string { return tx.Author }},
+	{id: 18, name: "Upřesnění", value: func(tx fio.Transaction) string { return tx.Specification }},
+	{id: 25, name: "Komentář", value: func(tx fio.Transaction) string { return tx.Comment }},
+	{id: 26, name: "BIC", value: func(tx fio.Transaction) string { return tx.BIC }},
+	{id: 17, name: "ID pokynu", value: func(tx fio.Transaction) string { return tx.OrderID }},
+	{id: 27, name: "Reference plátce", value: func(tx fio.Transaction) string { return tx.PayerReference }},
+}
+
+// charsets maps text formats to their charset, formats not listed are binary.
+var charsets = map[fio.ExportFormat]string{
+	fio.XMLFormat:     "UTF-8",
+	fio.JSONFormat:    "UTF-8",
+	fio.CSVFormat:     "UTF-8",
+	fio.GPCFormat:     "windows-1250",
+	fio.HTMLFormat:    "UTF-8",
+	fio.OFXFormat:     "UTF-8",
+	fio.MT940Format:   "UTF-8",
+	fio.CBAXMLFormat:  "UTF-8",
+	fio.SBAXMLFormat:  "UTF-8",
+	fio.CAMT053Format: "UTF-8",
+}
+
+// renderers maps formats to their renderers, the ČBA and SBA XML formats
+// are national profiles of camt.053 so they share its renderer.
+var renderers = map[fio.ExportFormat]func(w io.Writer, resp *fio.TransactionsResponse) error{
+	fio.XMLFormat:     renderXML,
+	fio.JSONFormat:    renderJSON,
+	fio.CSVFormat:     renderCSV,
+	fio.GPCFormat:     renderGPC,
+	fio.HTMLFormat:    renderHTML,
+	fio.OFXFormat:     renderOFX,
+	fio.MT940Format:   renderMT940,
+	fio.CBAXMLFormat:  renderCAMT053,
+	fio.SBAXMLFormat:  renderCAMT053,
+	fio.PDFFormat:     renderPDF,
+	fio.CAMT053Format: renderCAMT053,
+}
+
+// endpoint identifies the route serving the transactions.
+type endpoint int
+
+const (
+	periodsEndpoint endpoint = iota
+	statementEndpoint
+	lastEndpoint
+)
+
+// statementOnlyFormats are available only for official statements.
+var statementOnlyFormats = map[fio.ExportFormat]bool{
+	fio.PDFFormat:     true,
+	fio.CAMT053Format: true,
+}
+
+// writeStatement renders resp in format given by the file extension, formats
+// not available for the endpoint are not found. It reports whether resp was written.
+func writeStatement(w http.ResponseWriter, ep endpoint, file string, resp *fio.TransactionsResponse) bool {
+	name, ext, _ := strings.Cut(file, ".")
+	format := fio.ExportFormat(ext)
+	render, ok := renderers[format]
+	if ep != statementEndpoint && statementOnlyFormats[format] {
+		ok = false
+	}
+	if name != "transactions" || !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return false
+	}
+
+	contentType := format.ContentType()
+	if charset, ok := charsets[format]; ok {
+		contentType += ";charset=" + charset
+	}
+	w.Header().Set("Content-Type", contentType)
+	_ = render(w, resp)
+	return true
+}
+
+func renderXML(w io.Writer, resp *fio.TransactionsResponse) error {
+	info := resp.Info
+	b := new(strings.Builder)
+	b.WriteString(xml.Header)
+	b.WriteString("<AccountStatement>\n  <Info>\n")
+	writeXMLElement(b, "accountId", strconv.FormatInt(info.AccountID, 10))
+	writeXMLElement(b, "bankId", info.BankID)
+	writeXMLElement(b, "currency", info.Currency)
+	writeXMLElement(b, "iban", info.IBAN)
+	writeXMLElement(b, "bic", info.BIC)
+	writeXMLElement(b, "openingBalance", fmtAmount(info.OpeningBalance))
+	writeXMLElement(b, "closingBalance", fmtAmount(info.ClosingBalance))
+	writeXMLElement(b, "dateStart", info.DateStart.Format(xmlTimeFormat))
+	writeXMLElement(b, "dateEnd", info.DateEnd.Format(xmlTimeFormat))
+	writeXMLOptional(b, "yearList", info.YearList)
+	writeXMLOptional(b, "idList", info.IDList)
+	writeXMLOptional(b, "idFrom", info.IDFrom)
+	writeXMLOptional(b, "idTo", info.IDTo)
+	writeXMLOptional(b, "idLastDownload", info.IDLastDownload)
+	b.WriteString("  </Info>\n  <TransactionList>\n")
+	for _, tx := range resp.Transactions {
+		b.WriteString("    <Transaction>\n")
+		for _, col := range columns {
+			v := col.value(tx)
+			if v == "" {
+				continue
+			}
+			fmt.Fprintf(b, "      <column_%d name=\"%v\" id=\"%d\">", col.id, col.name, col.id)
+			_ = xml.EscapeText(b, []byte(v))
+			fmt.Fprintf(b, "</column_%d>\n", col.id)
+		}
+		b.WriteString("    </Transaction>\n")
+	}
+	b.WriteString("  </TransactionList>\n</AccountStatement>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeXMLElement(b *strings.Builder, name string, value string) {
+	fmt.Fprintf(b, "    <%v>", name)
+	_ = xml.EscapeText(b, []byte(value))
+	fmt.Fprintf(b, "</%v>\n", name)
+}
+
+func writeXMLOptional(b *strings.Builder, name string, value int64) {
+	if value != 0 {
+		writeXMLElement(b, name, strconv.FormatInt(value, 10))
+	}
+}
+
+type jsonColumn struct {
+	Value interface{} `json:"value"`
+	Name  string      `json:"name"`
+	ID    int         `json:"id"`
+}
+
+func renderJSON(w io.Writer, resp *fio.TransactionsResponse) error {
+	info := resp.Info
+	txs := make([]map[string]*jsonColumn, 0, len(resp.Transactions))
+	for _, tx := range resp.Transactions {
+		m := make(map[string]*jsonColumn, len(columns))
+		for _, col := range columns {
+			key := fmt.Sprintf("column%d", col.id)
+			v := col.value(tx)
+			switch {
+			case v == "":
+				m[key] = nil
+			case col.id == 0:
+				m[key] = &jsonColumn{Value: tx.Date.Format(jsonTimeFormat), Name: col.name, ID: col.id}
+			case col.number:
+				m[key] = &jsonColumn{Value: json.Number(v), Name: col.name, ID: col.id}
+			default:
+				m[key] = &jsonColumn{Value: v, Name: col.name, ID: col.id}
+			}
+		}
+		txs = append(txs, m)
+	}
+
+	doc := map[string]interface{}{
+		"accountStatement": map[string]interface{}{
+			"info": map[string]interface{}{
+				"accountId":      strconv.FormatInt(info.AccountID, 10),
+				"bankId":         info.BankID,
+				"currency":       info.Currency,
+				"iban":           info.IBAN,
+				"bic":            info.BIC,
+				"openingBalance": json.Number(fmtAmount(info.OpeningBalance)),
+				"closingBalance": json.Number(fmtAmount(info.ClosingBalance)),
+				"dateStart":      info.DateStart.Format(jsonTimeFormat),
+				"dateEnd":        info.DateEnd.Format(jsonTimeFormat),
+				"yearList":       jsonOptional(info.YearList),
+				"idList":         jsonOptional(info.IDList),
+				"idFrom":         jsonOptional(info.IDFrom),
+				"idTo":           jsonOptional(info.IDTo),
+				"idLastDownload": jsonOptional(info.IDLastDownload),
+			},
+			"transactionList": map[string]interface{}{
+				"transaction": txs,
+			},
+		},
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+func jsonOptional(v int64) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+func renderCSV(w io.Writer, resp *fio.TransactionsResponse) error {
+	info := resp.Info
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	cw.UseCRLF = true
+
+	header := [][]string{
+		{"accountId", strconv.FormatInt(info.AccountID, 10)},
+		{"bankId", info.BankID},
+		{"currency", info.Currency},
+		{"iban", info.IBAN},
+		{"bic", info.BIC},
+		{"openingBalance", fmtCSVAmount(info.OpeningBalance)},
+		{"closingBalance", fmtCSVAmount(info.ClosingBalance)},
+		{"dateStart", info.DateStart.Format(csvDateFormat)},
+		{"dateEnd", info.DateEnd.Format(csvDateFormat)},
+		{"idFrom", csvOptional(info.IDFrom)},
+		{"idTo", csvOptional(info.IDTo)},
+	}
+	if err := cw.WriteAll(header); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "\r\n"); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(columns))
+	for _, col := range columns {
+		names = append(names, col.name)
+	}
+	if err := cw.Write(names); err != nil {
+		return err
+	}
+	for _, tx := range resp.Transactions {
+		record := make([]string, 0, len(columns))
+		for _, col := range columns {
+			switch col.id {
+			case 0:
+				record = append(record, tx.Date.Format(csvDateFormat))
+			case 1:
+				record = append(record, fmtCSVAmount(tx.Amount))
+			default:
+				record = append(record, col.value(tx))
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func fmtCSVAmount(d decimal.Decimal) string {
+	return strings.Replace(fmtAmount(d), ".", ",", 1)
+}
+
+func csvOptional(v int64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+func renderGPC(w io.Writer, resp *fio.TransactionsResponse) error {
+	info := resp.Info
+	account := strconv.FormatInt(info.AccountID, 10)
+
+	var debit, credit decimal.Decimal
+	for _, tx := range resp.Transactions {
+		if tx.Amount.IsNegative() {
+			debit = debit.Sub(tx.Amount)
+		} else {
+			credit = credit.Add(tx.Amount)
+		}
+	}
+
+	b := new(strings.Builder)
+	b.WriteString("074")
+	b.WriteString(gpcNumber(account, 16))
+	b.WriteString(gpcText(info.IBAN, 20))
+	b.WriteString(info.DateStart.AddDate(0, 0, -1).Format(gpcDateFormat))
+	b.WriteString(gpcAmount(info.OpeningBalance, 14))
+	b.WriteString(gpcSign(info.OpeningBalance))
+	b.WriteString(gpcAmount(info.ClosingBalance, 14))
+	b.WriteString(gpcSign(info.ClosingBalance))
+	b.WriteString(gpcAmount(debit, 14))
+	b.WriteString("0")
+	b.WriteString(gpcAmount(credit, 14))
+	b.WriteString("0")
+	b.WriteString(gpcNumber(strconv.FormatInt(info.IDList, 10), 3))
+	b.WriteString(info.DateEnd.Format(gpcDateFormat))
+	b.WriteString(strings.Repeat(" ", 14))
+	b.WriteString("\r\n")
+
+	for _, tx := range resp.Transactions {
+		code := "2"
+		if tx.Amount.IsNegative() {
+			code = "1"
+		}
+
+		counterAccount := strings.ReplaceAll(tx.Account, "-", "")
+		b.WriteString("075")
+		b.WriteString(gpcNumber(account, 16))
+		b.WriteString(gpcNumber(counterAccount, 16))
+		b.WriteString(gpcNumber(strconv.FormatInt(tx.ID, 10), 13))
+		b.WriteString(gpcAmount(tx.Amount.Abs(), 12))
+		b.WriteString(code)
+		b.WriteString(gpcNumber(tx.VariableSymbol, 10))
+		b.WriteString("00")
+		b.WriteString(gpcNumber(tx.BankCode, 4))
+		b.WriteString(gpcNumber(tx.ConstantSymbol, 4))
+		b.WriteString(gpcNumber(tx.SpecificSymbol, 10))
+		b.WriteString(tx.Date.Format(gpcDateFormat))
+		b.WriteString(gpcText(tx.AccountName, 20))
+		b.WriteString("0")
+		b.WriteString(gpcNumber(gpc.CurrencyCode(tx.Currency), 4))
+		b.WriteString(tx.Date.Format(gpcDateFormat))
+		b.WriteString("\r\n")
+	}
+
+	_, err := w.Write(gpc.EncodeWindows1250(b.String()))
+	return err
+}
+
+func gpcNumber(s string, n int) string {
+	if len(s) > n {
+		return s[len(s)-n:]
+	}
+	return strings.Repeat("0", n-len(s)) + s
+}
+
+func gpcText(s string, n int) string {
+	if utf8.RuneCountInString(s) > n {
+		return string([]rune(s)[:n])
+	}
+	return s + strings.Repeat(" ", n-utf8.RuneCountInString(s))
+}
+
+func gpcAmount(d decimal.Decimal, n int) string {
+	return gpcNumber(d.Abs().Shift(2).Truncate(0).String(), n)
+}
+
+func gpcSign(d decimal.Decimal) string {
+	if d.IsNegative() {
+		return "-"
+	}
+	return "+"
+}
+
+func renderHTML(w io.Writer, resp *fio.TransactionsResponse) error {
+	b := new(strings.Builder)
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>")
+	b.WriteString(html.EscapeString(resp.Info.IBAN))
+	b.WriteString("</title></head>\n<body>\n<table>\n<tr>")
+	for _, col := range columns {
+		fmt.Fprintf(b, "<th>%v</th>", html.EscapeString(col.name))
+	}
+	b.WriteString("</tr>\n")
+	for _, tx := range resp.Transactions {
+		b.WriteString("<tr>")
+		for _, col := range columns {
+			fmt.Fprintf(b, "<td>%v</td>", html.EscapeString(col.value(tx)))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</table>\n</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func renderOFX(w io.Writer, resp *fio.TransactionsResponse) error {
+	info := resp.Info
+	b := new(strings.Builder)
+	b.WriteString("OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\nSECURITY:NONE\r\nENCODING:UTF-8\r\n")
+	b.WriteString("CHARSET:NONE\r\nCOMPRESSION:NONE\r\nOLDFILEUID:NONE\r\nNEWFILEUID:NONE\r\n\r\n")
+	b.WriteString("<OFX>\r\n<SIGNONMSGSRSV1>\r\n<SONRS>\r\n<STATUS>\r\n<CODE>0\r\n<SEVERITY>INFO\r\n</STATUS>\r\n")
+	fmt.Fprintf(b, "<DTSERVER>%v\r\n<LANGUAGE>CES\r\n</SONRS>\r\n</SIGNONMSGSRSV1>\r\n", time.Now().Format("20060102150405"))
+	b.WriteString("<BANKMSGSRSV1>\r\n<STMTTRNRS>\r\n<TRNUID>0\r\n<STATUS>\r\n<CODE>0\r\n<SEVERITY>INFO\r\n</STATUS>\r\n<STMTRS>\r\n")
+	fmt.Fprintf(b, "<CURDEF>%v\r\n", info.Currency)
+	fmt.Fprintf(b, "<BANKACCTFROM>\r\n<BANKID>%v\r\n<ACCTID>%v\r\n<ACCTTYPE>CHECKING\r\n</BANKACCTFROM>\r\n", info.BankID, info.AccountID)
+	fmt.Fprintf(b, "<BANKTRANLIST>\r\n<DTSTART>%v\r\n<DTEND>%v\r\n", info.DateStart.Format(ofxDateFormat), info.DateEnd.Format(ofxDateFormat))
+	for _, tx := range resp.Transactions {
+		typ := "CREDIT"
+		if tx.Amount.IsNegative() {
+			typ = "DEBIT"
+		}
+		b.WriteString("<STMTTRN>\r\n")
+		fmt.Fprintf(b, "<TRNTYPE>%v\r\n<DTPOSTED>%v\r\n<TRNAMT>%v\r\n<FITID>%v\r\n", typ, tx.Date.Format(ofxDateFormat), fmtAmount(tx.Amount), tx.ID)
+		if tx.AccountName != "" {
+			fmt.Fprintf(b, "<NAME>%v\r\n", html.EscapeString(tx.AccountName))
+		}
+		if tx.RecipientMessage != "" {
+			fmt.Fprintf(b, "<MEMO>%v\r\n", html.EscapeString(tx.RecipientMessage))
+		}
+		b.WriteString("</STMTTRN>\r\n")
+	}
+	b.WriteString("</BANKTRANLIST>\r\n")
+	fmt.Fprintf(b, "<LEDGERBAL>\r\n<BALAMT>%v\r\n<DTASOF>%v\r\n</LEDGERBAL>\r\n", fmtAmount(info.ClosingBalance), info.DateEnd.Format(ofxDateFormat))
+	b.WriteString("</STMTRS>\r\n</STMTTRNRS>\r\n</BANKMSGSRSV1>\r\n</OFX>\r\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func renderMT940(w io.Writer, resp *fio.TransactionsResponse) error {
+	info := resp.Info
+	b := new(strings.Builder)
+	fmt.Fprintf(b, ":20:%d\r\n", info.IDTo)
+	fmt.Fprintf(b, ":25:%v\r\n", info.IBAN)
+	fmt.Fprintf(b, ":28C:%d\r\n", info.IDList)
+	fmt.Fprintf(b, ":60F:%v\r\n", mt940Balance(info.OpeningBalance, info.DateStart, info.Currency))
+	for _, tx := range resp.Transactions {
+		ref := cmp.Or(tx.VariableSymbol, "NONREF")
+		fmt.Fprintf(b, ":61:%v%v%v%vNTRF%v//%d\r\n", tx.Date.Format(mt940DateFormat), tx.Date.Format("0102"),
+			mt940Mark(tx.Amount), mt940Amount(tx.Amount), ref, tx.ID)
+		if details := strings.TrimSpace(tx.AccountName + " " + tx.RecipientMessage); details != "" {
+			fmt.Fprintf(b, ":86:%v\r\n", details)
+		}
+	}
+	fmt.Fprintf(b, ":62F:%v\r\n", mt940Balance(info.ClosingBalance, info.DateEnd, info.Currency))
+	b.WriteString("-\r\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mt940Balance(d decimal.Decimal, t time.Time, currency string) string {
+	return mt940Mark(d) + t.Format(mt940DateFormat) + currency + mt940Amount(d)
+}
+
+func mt940Mark(d decimal.Decimal) string {
+	if d.IsNegative() {
+		return "D"
+	}
+	return "C"
+}
+
+func mt940Amount(d decimal.Decimal) string {
+	return strings.Replace(d.Abs().StringFixed(2), ".", ",", 1)
+}
+
+func renderCAMT053(w io.Writer, resp *fio.TransactionsResponse) error {
+	return fio.WriteCAMT053(w, resp, fio.CAMT053Options{})
+}
+
+// renderPDF renders single page PDF document listing the transactions,
+// characters outside ASCII are replaced as the standard fonts lack them.
+func renderPDF(w io.Writer, resp *fio.TransactionsResponse) error {
+	info := resp.Info
+	lines := []string{
+		fmt.Sprintf("%v %v - %v", info.IBAN, info.DateStart.Format(dateFormat), info.DateEnd.Format(dateFormat)),
+		fmt.Sprintf("Opening balance %v %v", fmtAmount(info.OpeningBalance), info.Currency),
+	}
+	for _, tx := range resp.Transactions {
+		lines = append(lines, fmt.Sprintf("%d %v %v %v %v", tx.ID, tx.Date.Format(dateFormat), fmtAmount(tx.Amount), tx.Currency, tx.AccountName))
+	}
+	lines = append(lines, fmt.Sprintf("Closing balance %v %v", fmtAmount(info.ClosingBalance), info.Currency))
+
+	content := new(strings.Builder)
+	content.WriteString("BT\n/F1 10 Tf\n14 TL\n50 800 Td\n")
+	for _, line := range lines {
+		fmt.Fprintf(content, "(%v) Tj T*\n", pdfText(line))
+	}
+	content.WriteString("ET")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%v\nendstream", content.Len(), content.String()),
+	}
+
+	b := new(strings.Builder)
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(b, "%d 0 obj\n%v\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%EOF\n", len(objects)+1, xref)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// pdfText escapes s for PDF string literal, non ASCII characters are replaced by '?'.
+func pdfText(s string) string {
+	b := new(strings.Builder)
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= utf8.RuneSelf:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,468 @@
+// Package fiotest provides an in-process fake of the Fio Banka API for testing.
+//
+// The fake server holds accounts and their transactions in memory, serves the
+// transactions endpoints in all the export formats, tracks the last download
+// cursor of every account, enforces the rate limit and accepts payment imports.
+package fiotest
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jbub/fio"
+)
+
+const dateFormat = "2006-01-02"
+
+// rateLimitTolerance is the fraction of RateLimit by which requests may arrive early.
+// The client limiter spaces requests by their send time while the server sees their
+// arrival, the tolerance absorbs the transport and scheduling delays between the two
+// and grows with the interval, so it holds on loaded machines too.
+const rateLimitTolerance = 0.5
+
+// Statement represents official account statement.
+type Statement struct {
+	Year     int
+	ID       int
+	DateFrom time.Time
+	DateTo   time.Time
+}
+
+// Import represents payment orders import received by the server.
+type Import struct {
+	InstructionID string
+	Type          string
+	Data          []byte
+}
+
+type account struct {
+	info         fio.StatementInfo
+	transactions []fio.Transaction
+	statements   []Statement
+	imports      []Import
+	cursor       int64
+	lastRequest  time.Time
+}
+
+// NewServer starts and returns new fake fio API server, it should be closed after use.
+func NewServer() *Server {
+	s := &Server{
+		RateLimit: fio.DefaultRateLimitInterval,
+		accounts:  make(map[string]*account),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/rest/periods/{token}/{from}/{to}/{file}", s.handlePeriods)
+	mux.HandleFunc("GET /v1/rest/by-id/{token}/{year}/{id}/{file}", s.handleByID)
+	mux.HandleFunc("GET /v1/rest/lastStatement/{token}/statement", s.handleLastStatement)
+	mux.HandleFunc("GET /ib_api/rest/last/{token}/{file}", s.handleLast)
+	mux.HandleFunc("GET /v1/rest/set-last-id/{token}/{id}/", s.handleSetLastID)
+	mux.HandleFunc("GET /v1/rest/set-last-date/{token}/{date}/", s.handleSetLastDate)
+	mux.HandleFunc("POST /v1/rest/import/", s.handleImport)
+	s.mux = mux
+
+	s.server = httptest.NewServer(s)
+	s.URL = s.server.URL
+	s.limiter = fio.NewRateLimiter(s.RateLimit)
+	return s
+}
+
+// Server is a fake fio API server.
+type Server struct {
+	// URL is the base URL of the server.
+	URL string
+
+	// RateLimit is the minimal interval between two requests made with the
+	// same token, zero disables the rate limit. Requests arriving up to half
+	// of the interval early are accepted, see rateLimitTolerance. It must be
+	// set before the first request is made.
+	RateLimit time.Duration
+
+	server  *httptest.Server
+	mux     *http.ServeMux
+	limiter *fio.RateLimiter
+
+	mu                sync.Mutex
+	accounts          map[string]*account
+	faults            []*Fault
+	lastInstructionID int
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns fio client configured to talk to the server using token.
+// All the returned clients share rate limiter matching the server RateLimit.
+func (s *Server) Client(token string) *fio.Client {
+	s.mu.Lock()
+	if s.limiter.Interval() != s.RateLimit {
+		s.limiter = fio.NewRateLimiter(s.RateLimit)
+	}
+	limiter := s.limiter
+	s.mu.Unlock()
+
+	c := fio.NewClient(token, s.server.Client())
+	c.BaseURL, _ = url.Parse(s.URL + "/")
+	c.Limiter = limiter
+	return c
+}
+
+// AddAccount registers account accessible with token, info holds the account
+// details and OpeningBalance is the balance before the first transaction.
+func (s *Server) AddAccount(token string, info fio.StatementInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts[token] = &account{info: info}
+}
+
+// AddTransactions adds transactions to the account, transactions without ID
+// are assigned the next available one.
+func (s *Server) AddTransactions(token string, txs ...fio.Transaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.mustAccount(token)
+	for _, tx := range txs {
+		if tx.ID == 0 {
+			tx.ID = acc.lastID() + 1
+		}
+		if tx.Currency == "" {
+			tx.Currency = acc.info.Currency
+		}
+		acc.transactions = append(acc.transactions, tx)
+	}
+	slices.SortStableFunc(acc.transactions, func(a, b fio.Transaction) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// AddStatement adds official statement to the account.
+func (s *Server) AddStatement(token string, st Statement) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.mustAccount(token)
+	acc.statements = append(acc.statements, st)
+}
+
+// LastDownloadID returns the last download cursor of the account.
+func (s *Server) LastDownloadID(token string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.mustAccount(token).cursor
+}
+
+// Imports returns payment imports received for the account.
+func (s *Server) Imports(token string) []Import {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.mustAccount(token).imports)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f, ok := s.fault(r); ok {
+		s.serveFault(w, r, f)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) mustAccount(token string) *account {
+	acc, ok := s.accounts[token]
+	if !ok {
+		panic(fmt.Sprintf("fiotest: unknown account: %v", token))
+	}
+	return acc
+}
+
+// account returns the account of token and enforces the rate limit,
+// it writes the error response and returns nil on failure.
+func (s *Server) account(w http.ResponseWriter, token string) *account {
+	acc, ok := s.accounts[token]
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil
+	}
+
+	now := time.Now()
+	minInterval := time.Duration(float64(s.RateLimit) * (1 - rateLimitTolerance))
+	if s.RateLimit > 0 && !acc.lastRequest.IsZero() && now.Sub(acc.lastRequest) < minInterval {
+		w.WriteHeader(http.StatusConflict)
+		return nil
+	}
+	acc.lastRequest = now
+	return acc
+}
+
+func (s *Server) handlePeriods(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.account(w, r.PathValue("token"))
+	if acc == nil {
+		return
+	}
+
+	from, err1 := time.Parse(dateFormat, r.PathValue("from"))
+	to, err2 := time.Parse(dateFormat, r.PathValue("to"))
+	if err1 != nil || err2 != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	writeStatement(w, periodsEndpoint, r.PathValue("file"), acc.period(from, to))
+}
+
+func (s *Server) handleByID(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.account(w, r.PathValue("token"))
+	if acc == nil {
+		return
+	}
+
+	year, _ := strconv.Atoi(r.PathValue("year"))
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	idx := slices.IndexFunc(acc.statements, func(st Statement) bool {
+		return st.Year == year && st.ID == id
+	})
+	if idx < 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	st := acc.statements[idx]
+	resp := acc.period(st.DateFrom, st.DateTo)
+	resp.Info.YearList = int64(st.Year)
+	resp.Info.IDList = int64(st.ID)
+	writeStatement(w, statementEndpoint, r.PathValue("file"), resp)
+}
+
+func (s *Server) handleLastStatement(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.account(w, r.PathValue("token"))
+	if acc == nil {
+		return
+	}
+
+	if len(acc.statements) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	last := slices.MaxFunc(acc.statements, func(a, b Statement) int {
+		if a.Year != b.Year {
+			return a.Year - b.Year
+		}
+		return a.ID - b.ID
+	})
+	fmt.Fprintf(w, "%d,%d", last.Year, last.ID)
+}
+
+func (s *Server) handleLast(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.account(w, r.PathValue("token"))
+	if acc == nil {
+		return
+	}
+
+	var txs []fio.Transaction
+	for _, tx := range acc.transactions {
+		if tx.ID > acc.cursor {
+			txs = append(txs, tx)
+		}
+	}
+
+	opening := acc.balance(func(tx fio.Transaction) bool {
+		return tx.ID <= acc.cursor
+	})
+	resp := acc.response(txs, opening)
+	resp.Info.IDLastDownload = acc.cursor
+	if len(txs) > 0 {
+		resp.Info.DateStart = txs[0].Date
+		resp.Info.DateEnd = txs[len(txs)-1].Date
+	}
+	// the cursor is moved only when the transactions were sent
+	if writeStatement(w, lastEndpoint, r.PathValue("file"), resp) && len(txs) > 0 {
+		acc.cursor = txs[len(txs)-1].ID
+	}
+}
+
+func (s *Server) handleSetLastID(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.account(w, r.PathValue("token"))
+	if acc == nil {
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	acc.cursor = id
+}
+
+func (s *Server) handleSetLastDate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.account(w, r.PathValue("token"))
+	if acc == nil {
+		return
+	}
+
+	date, err := time.Parse(dateFormat, r.PathValue("date"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	acc.cursor = 0
+	for _, tx := range acc.transactions {
+		if fmtDate(tx.Date) <= fmtDate(date) {
+			acc.cursor = tx.ID
+		}
+	}
+}
+
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	acc := s.account(w, r.FormValue("token"))
+	if acc == nil {
+		return
+	}
+
+	f, _, err := r.FormFile("file")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.lastInstructionID++
+	imp := Import{
+		InstructionID: strconv.Itoa(s.lastInstructionID),
+		Type:          r.FormValue("type"),
+		Data:          data,
+	}
+	acc.imports = append(acc.imports, imp)
+
+	w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+	writeImportResponse(w, imp)
+}
+
+func (a *account) lastID() int64 {
+	if len(a.transactions) == 0 {
+		return 0
+	}
+	return a.transactions[len(a.transactions)-1].ID
+}
+
+// response returns response with transactions and balances starting at opening.
+func (a *account) response(txs []fio.Transaction, opening decimal.Decimal) *fio.TransactionsResponse {
+	resp := &fio.TransactionsResponse{
+		Info:         a.info,
+		Transactions: txs,
+	}
+	resp.Info.OpeningBalance = opening
+	resp.Info.ClosingBalance = opening
+	for _, tx := range txs {
+		resp.Info.ClosingBalance = resp.Info.ClosingBalance.Add(tx.Amount)
+	}
+	if len(txs) > 0 {
+		resp.Info.IDFrom = txs[0].ID
+		resp.Info.IDTo = txs[len(txs)-1].ID
+	}
+	return resp
+}
+
+// balance returns the account balance after transactions accepted by fn.
+func (a *account) balance(fn func(tx fio.Transaction) bool) decimal.Decimal {
+	balance := a.info.OpeningBalance
+	for _, tx := range a.transactions {
+		if fn(tx) {
+			balance = balance.Add(tx.Amount)
+		}
+	}
+	return balance
+}
+
+func (a *account) period(from time.Time, to time.Time) *fio.TransactionsResponse {
+	var txs []fio.Transaction
+	for _, tx := range a.transactions {
+		if d := fmtDate(tx.Date); d >= fmtDate(from) && d <= fmtDate(to) {
+			txs = append(txs, tx)
+		}
+	}
+
+	opening := a.balance(func(tx fio.Transaction) bool {
+		return fmtDate(tx.Date) < fmtDate(from)
+	})
+	resp := a.response(txs, opening)
+	resp.Info.DateStart = from
+	resp.Info.DateEnd = to
+	return resp
+}
+
+func writeImportResponse(w io.Writer, imp Import) {
+	var orders struct {
+		Orders struct {
+			Items []struct{} `xml:",any"`
+		} `xml:"Orders"`
+	}
+	if imp.Type == string(fio.XMLImportFormat) {
+		_ = xml.NewDecoder(bytes.NewReader(imp.Data)).Decode(&orders)
+	}
+
+	buf := new(strings.Builder)
+	buf.WriteString(xml.Header)
+	buf.WriteString("<responseImport>\n  <result>\n    <errorCode>0</errorCode>\n")
+	fmt.Fprintf(buf, "    <idInstruction>%v</idInstruction>\n    <status>ok</status>\n  </result>\n", imp.InstructionID)
+	buf.WriteString("  <ordersDetails>\n")
+	for i := range orders.Orders.Items {
+		fmt.Fprintf(buf, "    <detail id=\"%d\"><messages><message status=\"ok\" errorCode=\"0\">OK</message></messages></detail>\n", i+1)
+	}
+	buf.WriteString("  </ordersDetails>\n</responseImport>\n")
+	_, _ = io.WriteString(w, buf.String())
+}
+
+func fmtDate(t time.Time) string {
+	return t.Format(dateFormat)
+}
+
+func fmtAmount(d decimal.Decimal) string {
+	return d.StringFixed(2)
+}
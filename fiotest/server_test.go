@@ -0,0 +1,357 @@
+package fiotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jbub/fio"
+)
+
+const testingToken = "xxxx"
+
+func newTestServer(t *testing.T) *Server {
+	srv := NewServer()
+	srv.RateLimit = 50 * time.Millisecond
+	t.Cleanup(srv.Close)
+
+	srv.AddAccount(testingToken, fio.StatementInfo{
+		AccountID:      1234562,
+		BankID:         "2010",
+		Currency:       "CZK",
+		IBAN:           "CZ7920100000000001234562",
+		BIC:            "FIOBCZPPXXX",
+		OpeningBalance: decimal.RequireFromString("100.00"),
+	})
+	srv.AddTransactions(testingToken,
+		fio.Transaction{Date: date(2024, 1, 5), Amount: decimal.RequireFromString("50.50"), AccountName: "Žluťoučký kůň", VariableSymbol: "123"},
+		fio.Transaction{Date: date(2024, 2, 10), Amount: decimal.RequireFromString("-20.00"), RecipientMessage: "rent"},
+		fio.Transaction{Date: date(2024, 3, 15), Amount: decimal.RequireFromString("5.25")},
+	)
+	return srv
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestServerByPeriod(t *testing.T) {
+	for _, format := range []fio.ExportFormat{fio.XMLFormat, fio.JSONFormat} {
+		t.Run(string(format), func(t *testing.T) {
+			srv := newTestServer(t)
+			client := srv.Client(testingToken)
+			client.ResponseFormat = format
+
+			resp, err := client.Transactions.ByPeriod(context.Background(), fio.ByPeriodOptions{
+				DateFrom: date(2024, 2, 1),
+				DateTo:   date(2024, 3, 31),
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(1234562), resp.Info.AccountID)
+			assert.Equal(t, "CZ7920100000000001234562", resp.Info.IBAN)
+			assert.Equal(t, "150.50", resp.Info.OpeningBalance.StringFixed(2))
+			assert.Equal(t, "135.75", resp.Info.ClosingBalance.StringFixed(2))
+			assert.Equal(t, int64(2), resp.Info.IDFrom)
+			assert.Equal(t, int64(3), resp.Info.IDTo)
+
+			require.Len(t, resp.Transactions, 2)
+			tx := resp.Transactions[0]
+			assert.Equal(t, int64(2), tx.ID)
+			assert.Equal(t, "2024-02-10", fmtDate(tx.Date))
+			assert.Equal(t, "-20.00", tx.Amount.StringFixed(2))
+			assert.Equal(t, "CZK", tx.Currency)
+			assert.Equal(t, "rent", tx.RecipientMessage)
+		})
+	}
+}
+
+func TestServerExport(t *testing.T) {
+	formats := map[fio.ExportFormat]string{
+		fio.CSVFormat:    "accountId;1234562",
+		fio.GPCFormat:    "0740000000001234562",
+		fio.HTMLFormat:   "<td>Žluťoučký kůň</td>",
+		fio.OFXFormat:    "<FITID>1",
+		fio.MT940Format:  ":61:2401050105C50,50NTRF123//1\r\n:86:Žluťoučký kůň",
+		fio.CBAXMLFormat: "<IBAN>CZ7920100000000001234562</IBAN>",
+		fio.SBAXMLFormat: "<IBAN>CZ7920100000000001234562</IBAN>",
+	}
+	for format, want := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			srv := newTestServer(t)
+			client := srv.Client(testingToken)
+
+			var buf bytes.Buffer
+			err := client.Transactions.Export(context.Background(), fio.ExportOptions{
+				DateFrom: date(2024, 1, 1),
+				DateTo:   date(2024, 1, 31),
+				Format:   format,
+			}, &buf)
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), want)
+		})
+	}
+}
+
+func TestServerExportStatement(t *testing.T) {
+	formats := map[fio.ExportFormat]string{
+		fio.PDFFormat:     "(1 2024-01-05 50.50 CZK ?lu?ou?k? k??) Tj",
+		fio.CAMT053Format: "<IBAN>CZ7920100000000001234562</IBAN>",
+	}
+	for format, want := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			srv := newTestServer(t)
+			srv.AddStatement(testingToken, Statement{Year: 2024, ID: 1, DateFrom: date(2024, 1, 1), DateTo: date(2024, 1, 31)})
+			client := srv.Client(testingToken)
+
+			var buf bytes.Buffer
+			err := client.Transactions.ExportStatement(context.Background(), fio.ExportStatementOptions{
+				Year:   2024,
+				ID:     1,
+				Format: format,
+			}, &buf)
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), want)
+		})
+	}
+}
+
+func TestServerStatementOnlyFormats(t *testing.T) {
+	for _, format := range []fio.ExportFormat{fio.PDFFormat, fio.CAMT053Format} {
+		t.Run(string(format), func(t *testing.T) {
+			srv := newTestServer(t)
+			srv.RateLimit = 0
+
+			for _, path := range []string{
+				"/v1/rest/periods/" + testingToken + "/2024-01-01/2024-01-31/transactions." + string(format),
+				"/ib_api/rest/last/" + testingToken + "/transactions." + string(format),
+			} {
+				resp, err := http.Get(srv.URL + path)
+				require.NoError(t, err)
+				resp.Body.Close()
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+			}
+			assert.Zero(t, srv.LastDownloadID(testingToken))
+		})
+	}
+}
+
+func TestServerExportContentType(t *testing.T) {
+	formats := map[fio.ExportFormat]string{
+		fio.XMLFormat:     "text/xml;charset=UTF-8",
+		fio.GPCFormat:     "text/plain;charset=windows-1250",
+		fio.MT940Format:   "text/plain;charset=UTF-8",
+		fio.CBAXMLFormat:  "text/xml;charset=UTF-8",
+		fio.SBAXMLFormat:  "text/xml;charset=UTF-8",
+		fio.PDFFormat:     "application/pdf",
+		fio.CAMT053Format: "text/xml;charset=UTF-8",
+	}
+	for format, want := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			srv := newTestServer(t)
+			srv.AddStatement(testingToken, Statement{Year: 2024, ID: 1, DateFrom: date(2024, 1, 1), DateTo: date(2024, 1, 31)})
+
+			resp, err := http.Get(srv.URL + "/v1/rest/by-id/" + testingToken + "/2024/1/transactions." + string(format))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, want, resp.Header.Get("Content-Type"))
+		})
+	}
+}
+
+func TestServerSinceLastDownload(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client(testingToken)
+	ctx := context.Background()
+
+	resp, err := client.Transactions.SinceLastDownload(ctx)
+	require.NoError(t, err)
+	assert.Len(t, resp.Transactions, 3)
+	assert.Equal(t, int64(3), srv.LastDownloadID(testingToken))
+
+	resp, err = client.Transactions.SinceLastDownload(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Transactions)
+
+	err = client.Transactions.SetLastDownloadID(ctx, fio.SetLastDownloadIDOptions{ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), srv.LastDownloadID(testingToken))
+
+	resp, err = client.Transactions.SinceLastDownload(ctx)
+	require.NoError(t, err)
+	require.Len(t, resp.Transactions, 2)
+	assert.Equal(t, int64(2), resp.Transactions[0].ID)
+	assert.Equal(t, "150.50", resp.Info.OpeningBalance.StringFixed(2))
+	assert.Equal(t, int64(1), resp.Info.IDLastDownload)
+
+	err = client.Transactions.SetLastDownloadDate(ctx, fio.SetLastDownloadDateOptions{Date: date(2024, 2, 10)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), srv.LastDownloadID(testingToken))
+}
+
+func TestServerStatements(t *testing.T) {
+	srv := newTestServer(t)
+	srv.AddStatement(testingToken, Statement{Year: 2024, ID: 1, DateFrom: date(2024, 1, 1), DateTo: date(2024, 1, 31)})
+	srv.AddStatement(testingToken, Statement{Year: 2024, ID: 2, DateFrom: date(2024, 2, 1), DateTo: date(2024, 2, 29)})
+	client := srv.Client(testingToken)
+	ctx := context.Background()
+
+	last, err := client.Transactions.LastStatement(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &fio.LastStatementResponse{Year: 2024, ID: 2}, last)
+
+	resp, err := client.Transactions.GetStatement(ctx, fio.GetStatementOptions{Year: 2024, ID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2024), resp.Info.YearList)
+	assert.Equal(t, int64(1), resp.Info.IDList)
+	require.Len(t, resp.Transactions, 1)
+	assert.Equal(t, "Žluťoučký kůň", resp.Transactions[0].AccountName)
+
+	_, err = client.Transactions.GetStatement(ctx, fio.GetStatementOptions{Year: 2023, ID: 1})
+	assert.True(t, errors.Is(err, fio.ErrNotFound))
+}
+
+func TestServerRateLimit(t *testing.T) {
+	srv := newTestServer(t)
+	srv.RateLimit = time.Hour
+	client := srv.Client(testingToken)
+	client.Limiter = nil
+	client.Retry = nil
+	ctx := context.Background()
+
+	_, err := client.Transactions.SinceLastDownload(ctx)
+	require.NoError(t, err)
+
+	_, err = client.Transactions.SinceLastDownload(ctx)
+	assert.True(t, errors.Is(err, fio.ErrRateLimited))
+}
+
+func TestServerRateLimitTolerance(t *testing.T) {
+	srv := newTestServer(t)
+	srv.RateLimit = 200 * time.Millisecond
+	url := srv.URL + "/ib_api/rest/last/" + testingToken + "/transactions.xml"
+
+	get := func() int {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, get())
+	require.Equal(t, http.StatusConflict, get())
+
+	// arriving early by less than the tolerance is accepted
+	time.Sleep(150 * time.Millisecond)
+	require.Equal(t, http.StatusOK, get())
+}
+
+func TestServerUnknownToken(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client("unknown")
+
+	_, err := client.Transactions.SinceLastDownload(context.Background())
+	assert.True(t, errors.Is(err, fio.ErrInvalidToken))
+}
+
+func TestServerImport(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client(testingToken)
+
+	order := &fio.DomesticTransaction{
+		AccountFrom: "1234562",
+		Currency:    "CZK",
+		Amount:      decimal.RequireFromString("10.00"),
+		AccountTo:   "2212-2000000699",
+		BankCode:    "0300",
+		Date:        date(2024, 4, 1),
+	}
+	resp, err := client.Payments.Import(context.Background(), fio.ImportOptions{
+		Orders: []fio.Order{order, order},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1", resp.InstructionID)
+	assert.Equal(t, fio.ImportStatusOK, resp.Status)
+	assert.Len(t, resp.Details, 2)
+
+	imports := srv.Imports(testingToken)
+	require.Len(t, imports, 1)
+	assert.Equal(t, "xml", imports[0].Type)
+	assert.Contains(t, string(imports[0].Data), "<DomesticTransaction>")
+}
+
+func TestServerExportGPC(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client(testingToken)
+
+	var buf bytes.Buffer
+	err := client.Transactions.Export(context.Background(), fio.ExportOptions{
+		DateFrom: date(2024, 1, 1),
+		DateTo:   date(2024, 2, 29),
+		Format:   fio.GPCFormat,
+	}, &buf)
+	require.NoError(t, err)
+
+	resp, err := fio.ParseGPC(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234562), resp.Info.AccountID)
+	assert.Equal(t, "100.00", resp.Info.OpeningBalance.StringFixed(2))
+	assert.Equal(t, "130.50", resp.Info.ClosingBalance.StringFixed(2))
+	require.Len(t, resp.Transactions, 2)
+	assert.Equal(t, "Žluťoučký kůň", resp.Transactions[0].AccountName)
+	assert.Equal(t, "123", resp.Transactions[0].VariableSymbol)
+	assert.Equal(t, "-20.00", resp.Transactions[1].Amount.StringFixed(2))
+}
+
+func TestServerExportCSV(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client(testingToken)
+
+	var buf bytes.Buffer
+	err := client.Transactions.Export(context.Background(), fio.ExportOptions{
+		DateFrom: date(2024, 1, 1),
+		DateTo:   date(2024, 2, 29),
+		Format:   fio.CSVFormat,
+	}, &buf)
+	require.NoError(t, err)
+
+	resp, err := fio.ParseCSV(&buf, fio.ParseOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "CZ7920100000000001234562", resp.Info.IBAN)
+	assert.Equal(t, "130.50", resp.Info.ClosingBalance.StringFixed(2))
+	require.Len(t, resp.Transactions, 2)
+	assert.Equal(t, "Žluťoučký kůň", resp.Transactions[0].AccountName)
+	assert.Equal(t, "2024-02-10", fmtDate(resp.Transactions[1].Date))
+	assert.Equal(t, "rent", resp.Transactions[1].RecipientMessage)
+}
+
+func TestServerExportOFX(t *testing.T) {
+	srv := newTestServer(t)
+	client := srv.Client(testingToken)
+
+	var buf bytes.Buffer
+	err := client.Transactions.Export(context.Background(), fio.ExportOptions{
+		DateFrom: date(2024, 1, 1),
+		DateTo:   date(2024, 2, 29),
+		Format:   fio.OFXFormat,
+	}, &buf)
+	require.NoError(t, err)
+
+	resp, err := fio.ParseOFX(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234562), resp.Info.AccountID)
+	assert.Equal(t, "100.00", resp.Info.OpeningBalance.StringFixed(2))
+	assert.Equal(t, "130.50", resp.Info.ClosingBalance.StringFixed(2))
+	require.Len(t, resp.Transactions, 2)
+	assert.Equal(t, "Žluťoučký kůň", resp.Transactions[0].AccountName)
+	assert.Equal(t, "rent", resp.Transactions[1].RecipientMessage)
+}
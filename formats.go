@@ -1,14 +1,92 @@
 package fio
 
-// ExportFormat represents export formats supported by Export.
+import (
+	"fmt"
+)
+
+// ExportFormat represents export formats supported by Export and ExportStatement.
 type ExportFormat string
 
 // Supported ExportFormat types.
 const (
-	JSONFormat ExportFormat = "json"
-	XMLFormat  ExportFormat = "xml"
-	CSVFormat  ExportFormat = "csv"
-	GPCFormat  ExportFormat = "gpc"
-	HTMLFormat ExportFormat = "html"
-	OFXFormat  ExportFormat = "ofx"
+	JSONFormat   ExportFormat = "json"
+	XMLFormat    ExportFormat = "xml"
+	CSVFormat    ExportFormat = "csv"
+	GPCFormat    ExportFormat = "gpc"
+	HTMLFormat   ExportFormat = "html"
+	OFXFormat    ExportFormat = "ofx"
+	MT940Format  ExportFormat = "sta"
+	CBAXMLFormat ExportFormat = "cba_xml"
+	SBAXMLFormat ExportFormat = "sba_xml"
+
+	// Formats available only for statements.
+	PDFFormat     ExportFormat = "pdf"
+	CAMT053Format ExportFormat = "camt053"
+)
+
+type exportFormatInfo struct {
+	contentType   string
+	statementOnly bool
+}
+
+var exportFormats = map[ExportFormat]exportFormatInfo{
+	JSONFormat:    {contentType: "application/json"},
+	XMLFormat:     {contentType: "text/xml"},
+	CSVFormat:     {contentType: "text/csv"},
+	GPCFormat:     {contentType: "text/plain"},
+	HTMLFormat:    {contentType: "text/html"},
+	OFXFormat:     {contentType: "application/x-ofx"},
+	MT940Format:   {contentType: "text/plain"},
+	CBAXMLFormat:  {contentType: "text/xml"},
+	SBAXMLFormat:  {contentType: "text/xml"},
+	PDFFormat:     {contentType: "application/pdf", statementOnly: true},
+	CAMT053Format: {contentType: "text/xml", statementOnly: true},
+}
+
+// Validate checks whether the format is supported, use errors.Is with
+// ErrUnsupportedFormat to check the returned error.
+func (f ExportFormat) Validate() error {
+	if _, ok := exportFormats[f]; !ok {
+		return fmt.Errorf(`%w: "%v"`, ErrUnsupportedFormat, f)
+	}
+	return nil
+}
+
+// ContentType returns media type of the format, it is empty for unsupported formats.
+func (f ExportFormat) ContentType() string {
+	return exportFormats[f].contentType
+}
+
+// validatePeriod checks whether the format is available for transactions in date period.
+func (f ExportFormat) validatePeriod() error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	if exportFormats[f].statementOnly {
+		return fmt.Errorf(`%w: "%v" is available only for statements`, ErrUnsupportedFormat, f)
+	}
+	return nil
+}
+
+func (f ExportFormat) fileName() string {
+	return "transactions." + string(f)
+}
+
+// ImportFormat represents import formats supported by ImportFile.
+type ImportFormat string
+
+// Supported ImportFormat types.
+const (
+	XMLImportFormat     ImportFormat = "xml"
+	ABOImportFormat     ImportFormat = "abo"
+	Pain001ImportFormat ImportFormat = "pain001.xml"
 )
+
+func (f ImportFormat) fileName() string {
+	switch f {
+	case ABOImportFormat:
+		return "import.abo"
+	default:
+		return "import.xml"
+	}
+}
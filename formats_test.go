@@ -0,0 +1,26 @@
+package fio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFormatValidate(t *testing.T) {
+	for _, format := range []ExportFormat{JSONFormat, XMLFormat, CSVFormat, GPCFormat, HTMLFormat, OFXFormat, MT940Format, CBAXMLFormat, SBAXMLFormat, PDFFormat, CAMT053Format} {
+		require.NoError(t, format.Validate(), format)
+		require.NotEmpty(t, format.ContentType(), format)
+	}
+
+	err := ExportFormat("docx").Validate()
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+	require.Equal(t, `unsupported export format: "docx"`, err.Error())
+	require.Empty(t, ExportFormat("docx").ContentType())
+}
+
+func TestExportFormatValidatePeriod(t *testing.T) {
+	require.NoError(t, MT940Format.validatePeriod())
+	require.ErrorIs(t, PDFFormat.validatePeriod(), ErrUnsupportedFormat)
+	require.ErrorIs(t, CAMT053Format.validatePeriod(), ErrUnsupportedFormat)
+	require.ErrorIs(t, ExportFormat("docx").validatePeriod(), ErrUnsupportedFormat)
+}
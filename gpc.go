@@ -0,0 +1,173 @@
+package fio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/jbub/fio/internal/gpc"
+)
+
+const (
+	gpcHeaderRecord = "074"
+	gpcItemRecord   = "075"
+	gpcRecordLength = 128
+	gpcDateFormat   = "020106"
+)
+
+// ParseGPC parses statement exported in GPCFormat. GPC holds less details than the
+// other formats, only the fields present in its 074 header and 075 item records are set.
+func ParseGPC(r io.Reader) (*TransactionsResponse, error) {
+	resp := new(TransactionsResponse)
+	var hasHeader bool
+
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		record := strings.TrimRight(sc.Text(), "\r")
+		if record == "" {
+			continue
+		}
+		if len(record) < gpcRecordLength {
+			return nil, fmt.Errorf(`unable to parse gpc record %d: "%v"`, line, record)
+		}
+
+		var err error
+		switch record[:3] {
+		case gpcHeaderRecord:
+			if hasHeader {
+				return nil, fmt.Errorf("unexpected gpc header record %d", line)
+			}
+			hasHeader = true
+			err = parseGPCHeader(&resp.Info, record)
+		case gpcItemRecord:
+			var tx *Transaction
+			tx, err = parseGPCItem(record)
+			if tx != nil {
+				resp.Transactions = append(resp.Transactions, *tx)
+			}
+		default:
+			return nil, fmt.Errorf(`unexpected gpc record %d: "%v"`, line, record[:3])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse gpc record %d: %w", line, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !hasHeader {
+		return nil, errors.New("missing statement info")
+	}
+
+	if len(resp.Transactions) > 0 {
+		resp.Info.Currency = resp.Transactions[0].Currency
+		resp.Info.IDFrom = resp.Transactions[0].ID
+		resp.Info.IDTo = resp.Transactions[len(resp.Transactions)-1].ID
+	}
+	return resp, nil
+}
+
+func parseGPCHeader(info *StatementInfo, record string) (err error) {
+	if info.AccountID, err = parseInteger(record[3:19]); err != nil {
+		return err
+	}
+	previous, err := parseGPCDate(record[39:45])
+	if err != nil {
+		return err
+	}
+	info.DateStart = previous.AddDate(0, 0, 1)
+	if info.OpeningBalance, err = parseGPCAmount(record[45:59]); err != nil {
+		return err
+	}
+	if record[59] == '-' {
+		info.OpeningBalance = info.OpeningBalance.Neg()
+	}
+	if info.ClosingBalance, err = parseGPCAmount(record[60:74]); err != nil {
+		return err
+	}
+	if record[74] == '-' {
+		info.ClosingBalance = info.ClosingBalance.Neg()
+	}
+	if info.IDList, err = parseInteger(record[105:108]); err != nil {
+		return err
+	}
+	info.DateEnd, err = parseGPCDate(record[108:114])
+	return err
+}
+
+func parseGPCItem(record string) (*Transaction, error) {
+	var err error
+	tx := new(Transaction)
+	if tx.ID, err = parseInteger(record[35:48]); err != nil {
+		return nil, err
+	}
+	if tx.Amount, err = parseGPCAmount(record[48:60]); err != nil {
+		return nil, err
+	}
+
+	// 1 debit, 2 credit, 4 reversed debit, 5 reversed credit
+	switch record[60] {
+	case '1', '5':
+		tx.Amount = tx.Amount.Neg()
+	case '2', '4':
+	default:
+		return nil, fmt.Errorf(`unable to parse accounting code: "%c"`, record[60])
+	}
+
+	tx.Account = gpcAccount(record[19:35])
+	tx.VariableSymbol = trimGPCNumber(record[61:71])
+	tx.BankCode = trimGPCCode(record[73:77])
+	tx.ConstantSymbol = trimGPCCode(record[77:81])
+	tx.SpecificSymbol = trimGPCNumber(record[81:91])
+	tx.AccountName = strings.TrimSpace(gpc.DecodeWindows1250(record[97:117]))
+	tx.Currency = gpc.Currency(record[118:122])
+
+	// due date is optional, valuta is used instead when it is missing
+	date := record[122:128]
+	if trimGPCNumber(date) == "" {
+		date = record[91:97]
+	}
+	if tx.Date, err = parseGPCDate(date); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func parseGPCAmount(s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return d.Shift(-2), nil
+}
+
+func parseGPCDate(s string) (time.Time, error) {
+	return time.ParseInLocation(gpcDateFormat, s, xmlGMTLocation)
+}
+
+// gpcAccount formats account number, the first six digits are the prefix.
+func gpcAccount(s string) string {
+	prefix := trimGPCNumber(s[:6])
+	number := trimGPCNumber(s[6:])
+	if prefix == "" {
+		return number
+	}
+	return prefix + "-" + number
+}
+
+func trimGPCNumber(s string) string {
+	return strings.TrimLeft(strings.TrimSpace(s), "0")
+}
+
+// trimGPCCode returns s unless it consists of zeros only, leading zeros of codes are significant.
+func trimGPCCode(s string) string {
+	if trimGPCNumber(s) == "" {
+		return ""
+	}
+	return s
+}
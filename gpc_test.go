@@ -0,0 +1,71 @@
+package fio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const gpcStatement = "0740000000001234562Fio ucet            31012400000000010050+00000000001234-000000000112840000000000000000002290224              \r\n" +
+	"0750000000001234562002212200000069900260000000010000000020001000000012300030003080000000000100224\x8elu\x9dou\xe8k\xfd k\xf9\xf2       00203100224\r\n" +
+	"0750000000001234562000000000000000000260000000020000000001502000000000000000000000000000042150224                    00978000000\r\n"
+
+func TestParseGPC(t *testing.T) {
+	resp, err := ParseGPC(strings.NewReader(gpcStatement))
+	require.NoError(t, err)
+
+	info := resp.Info
+	require.Equal(t, int64(1234562), info.AccountID)
+	require.Equal(t, "CZK", info.Currency)
+	require.Equal(t, "100.50", info.OpeningBalance.StringFixed(2))
+	require.Equal(t, "-12.34", info.ClosingBalance.StringFixed(2))
+	require.Equal(t, "2024-02-01", info.DateStart.Format("2006-01-02"))
+	require.Equal(t, "2024-02-29", info.DateEnd.Format("2006-01-02"))
+	require.Equal(t, int64(2), info.IDList)
+	require.Equal(t, int64(26000000001), info.IDFrom)
+	require.Equal(t, int64(26000000002), info.IDTo)
+
+	require.Len(t, resp.Transactions, 2)
+
+	tx := resp.Transactions[0]
+	require.Equal(t, int64(26000000001), tx.ID)
+	require.Equal(t, "-20.00", tx.Amount.StringFixed(2))
+	require.Equal(t, "CZK", tx.Currency)
+	require.Equal(t, "2212-2000000699", tx.Account)
+	require.Equal(t, "0300", tx.BankCode)
+	require.Equal(t, "0308", tx.ConstantSymbol)
+	require.Equal(t, "123", tx.VariableSymbol)
+	require.Equal(t, "", tx.SpecificSymbol)
+	require.Equal(t, "Žluťoučký kůň", tx.AccountName)
+	require.Equal(t, "2024-02-10", tx.Date.Format("2006-01-02"))
+
+	tx = resp.Transactions[1]
+	require.Equal(t, "1.50", tx.Amount.StringFixed(2))
+	require.Equal(t, "EUR", tx.Currency)
+	require.Equal(t, "", tx.Account)
+	require.Equal(t, "", tx.BankCode)
+	require.Equal(t, "", tx.ConstantSymbol)
+	require.Equal(t, "42", tx.SpecificSymbol)
+	require.Equal(t, "", tx.AccountName)
+	require.Equal(t, "2024-02-15", tx.Date.Format("2006-01-02"))
+}
+
+func TestParseGPCErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{name: "missing header", data: gpcStatement[strings.Index(gpcStatement, "\n")+1:]},
+		{name: "short record", data: "074000\r\n"},
+		{name: "unknown record", data: strings.Replace(gpcStatement, "075", "076", 1)},
+		{name: "invalid accounting code", data: strings.Replace(gpcStatement, "0200010000000123", "0200090000000123", 1)},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			_, err := ParseGPC(strings.NewReader(cs.data))
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package gpc holds the Windows-1250 code page and the currency codes shared by
+// the GPC parser and the fake server, so the encoder and the decoder cannot drift apart.
+package gpc
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// currencies maps ISO 4217 numeric codes used by GPC to currency codes.
+var currencies = map[string]string{
+	"0203": "CZK",
+	"0978": "EUR",
+	"0840": "USD",
+	"0826": "GBP",
+	"0756": "CHF",
+	"0985": "PLN",
+	"0348": "HUF",
+}
+
+// currencyCodes maps currency codes to ISO 4217 numeric codes.
+var currencyCodes = func() map[string]string {
+	codes := make(map[string]string, len(currencies))
+	for code, currency := range currencies {
+		codes[currency] = code
+	}
+	return codes
+}()
+
+// Currency returns currency of ISO 4217 numeric code, it is empty for unknown codes.
+func Currency(code string) string {
+	return currencies[code]
+}
+
+// CurrencyCode returns ISO 4217 numeric code of currency, it is empty for unknown currencies.
+func CurrencyCode(currency string) string {
+	return currencyCodes[currency]
+}
+
+// windows1250 maps upper half of Windows-1250 code page to runes.
+var windows1250 = [128]rune{
+	'€', '�', '‚', '�', '„', '…', '†', '‡',
+	'�', '‰', 'Š', '‹', 'Ś', 'Ť', 'Ž', 'Ź',
+	'�', '‘', '’', '“', '”', '•', '–', '—',
+	'�', '™', 'š', '›', 'ś', 'ť', 'ž', 'ź',
+	' ', 'ˇ', '˘', 'Ł', '¤', 'Ą', '¦', '§',
+	'¨', '©', 'Ş', '«', '¬', '­', '®', 'Ż',
+	'°', '±', '˛', 'ł', '´', 'µ', '¶', '·',
+	'¸', 'ą', 'ş', '»', 'Ľ', '˝', 'ľ', 'ż',
+	'Ŕ', 'Á', 'Â', 'Ă', 'Ä', 'Ĺ', 'Ć', 'Ç',
+	'Č', 'É', 'Ę', 'Ë', 'Ě', 'Í', 'Î', 'Ď',
+	'Đ', 'Ń', 'Ň', 'Ó', 'Ô', 'Ő', 'Ö', '×',
+	'Ř', 'Ů', 'Ú', 'Ű', 'Ü', 'Ý', 'Ţ', 'ß',
+	'ŕ', 'á', 'â', 'ă', 'ä', 'ĺ', 'ć', 'ç',
+	'č', 'é', 'ę', 'ë', 'ě', 'í', 'î', 'ď',
+	'đ', 'ń', 'ň', 'ó', 'ô', 'ő', 'ö', '÷',
+	'ř', 'ů', 'ú', 'ű', 'ü', 'ý', 'ţ', '˙',
+}
+
+// DecodeWindows1250 decodes s encoded in Windows-1250.
+func DecodeWindows1250(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x80 {
+			b.WriteByte(c)
+		} else {
+			b.WriteRune(windows1250[c-0x80])
+		}
+	}
+	return b.String()
+}
+
+// EncodeWindows1250 encodes s in Windows-1250, unsupported characters are replaced by '?'.
+func EncodeWindows1250(s string) []byte {
+	buf := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			buf = append(buf, byte(r))
+			continue
+		}
+
+		b := byte('?')
+		for i, c := range windows1250 {
+			if c == r && c != utf8.RuneError {
+				b = byte(0x80 + i)
+				break
+			}
+		}
+		buf = append(buf, b)
+	}
+	return buf
+}
@@ -0,0 +1,21 @@
+package gpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindows1250(t *testing.T) {
+	require.Equal(t, []byte{'Z', 0x9e, 0xe8, '?'}, EncodeWindows1250("Zžč☃"))
+	require.Equal(t, "Žluťoučký kůň", DecodeWindows1250(string(EncodeWindows1250("Žluťoučký kůň"))))
+}
+
+func TestCurrency(t *testing.T) {
+	for code, currency := range currencies {
+		require.Equal(t, currency, Currency(code))
+		require.Equal(t, code, CurrencyCode(currency))
+	}
+	require.Empty(t, Currency("0000"))
+	require.Empty(t, CurrencyCode("XXX"))
+}
@@ -0,0 +1,54 @@
+package fio
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MerchantTransactionsResponse represents response card terminal transactions.
+type MerchantTransactionsResponse struct {
+	Info         StatementInfo
+	Transactions []MerchantTransaction
+}
+
+// MerchantTransaction represents card payment made on the merchant terminal.
+type MerchantTransaction struct {
+	ID             int64
+	Date           time.Time
+	TerminalID     string
+	CardType       string
+	CardNumber     string
+	Currency       string
+	GrossAmount    decimal.Decimal
+	Fee            decimal.Decimal
+	NetAmount      decimal.Decimal
+	SettlementDate time.Time
+
+	// Extra holds columns unknown to the parser keyed by their ID,
+	// it is populated only in lenient parsing mode.
+	Extra map[string]Column
+}
+
+// MerchantService is a service for working with card terminal transactions of merchant accounts.
+type MerchantService struct {
+	client *Client
+}
+
+// ByPeriod returns card terminal transactions in date period.
+func (s *MerchantService) ByPeriod(ctx context.Context, opts ByPeriodOptions) (*MerchantTransactionsResponse, error) {
+	urlStr := s.client.buildURL("v1/rest/merchant", fmtDate(opts.DateFrom), fmtDate(opts.DateTo), "transactions.xml")
+	req, err := s.client.newGetRequest(ctx, urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	return parseMerchantTransactionsResponse(resp.Body, s.client.ParseOptions)
+}
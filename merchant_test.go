@@ -0,0 +1,82 @@
+package fio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const merchantTransactionsResponse = `
+<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<AccountStatement>
+  <Info>
+    <accountId>2000000000</accountId>
+    <bankId>2010</bankId>
+    <currency>CZK</currency>
+    <iban>CZ1220100000002000000000</iban>
+    <bic>FIOBCZPPXXX</bic>
+    <openingBalance>0.00</openingBalance>
+    <closingBalance>0.00</closingBalance>
+    <dateStart>2023-03-01+01:00</dateStart>
+    <dateEnd>2023-03-01+01:00</dateEnd>
+  </Info>
+  <TransactionList>
+    <Transaction>
+      <column_22 name="ID pohybu" id="22">25000000001</column_22>
+      <column_0 name="Datum" id="0">2023-03-01+01:00</column_0>
+      <column_1 name="Objem" id="1">250.00</column_1>
+      <column_14 name="Měna" id="14">CZK</column_14>
+      <column_28 name="ID terminálu" id="28">T0012345</column_28>
+      <column_29 name="Typ karty" id="29">VISA</column_29>
+      <column_30 name="Číslo karty" id="30">411111******1111</column_30>
+      <column_31 name="Poplatek" id="31">2.50</column_31>
+      <column_32 name="Částka po poplatku" id="32">247.50</column_32>
+      <column_33 name="Datum zúčtování" id="33">2023-03-02+01:00</column_33>
+    </Transaction>
+  </TransactionList>
+</AccountStatement>
+`
+
+func TestMerchantByPeriod(t *testing.T) {
+	setup()
+	defer teardown()
+
+	dateFrom := time.Now()
+	dateTo := time.Now()
+	urlStr := fmt.Sprintf("/v1/rest/merchant/%v/%v/%v/transactions.xml", testingToken, fmtDate(dateFrom), fmtDate(dateTo))
+
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, merchantTransactionsResponse)
+	})
+
+	opts := ByPeriodOptions{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+	resp, err := client.Merchant.ByPeriod(context.Background(), opts)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(2000000000), resp.Info.AccountID)
+	require.Equal(t, "CZ1220100000002000000000", resp.Info.IBAN)
+	require.Len(t, resp.Transactions, 1)
+
+	tx := resp.Transactions[0]
+	require.Equal(t, int64(25000000001), tx.ID)
+	require.True(t, time.Date(2023, time.March, 1, 0, 0, 0, 0, time.FixedZone("+0100", 60*60)).Equal(tx.Date))
+	require.Equal(t, "T0012345", tx.TerminalID)
+	require.Equal(t, "VISA", tx.CardType)
+	require.Equal(t, "411111******1111", tx.CardNumber)
+	require.Equal(t, "CZK", tx.Currency)
+	require.Equal(t, decimal.RequireFromString("250.00"), tx.GrossAmount)
+	require.Equal(t, decimal.RequireFromString("2.50"), tx.Fee)
+	require.Equal(t, decimal.RequireFromString("247.50"), tx.NetAmount)
+	require.True(t, time.Date(2023, time.March, 2, 0, 0, 0, 0, time.FixedZone("+0100", 60*60)).Equal(tx.SettlementDate))
+	require.Nil(t, tx.Extra)
+}
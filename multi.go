@@ -0,0 +1,123 @@
+package fio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// NewMultiClient returns client for multiple accounts, tokens are keyed by account name.
+func NewMultiClient(tokens map[string]string, client *http.Client) *MultiClient {
+	limiter := NewRateLimiter(DefaultRateLimitInterval)
+	clients := make(map[string]*Client, len(tokens))
+	for name, token := range tokens {
+		c := NewClient(token, client)
+		c.Limiter = limiter
+		clients[name] = c
+	}
+	return &MultiClient{clients: clients}
+}
+
+// MultiClient is fio http api client managing multiple accounts. Every account
+// has its own Client, requests of different accounts are made concurrently
+// while the rate limit is enforced independently for each token.
+type MultiClient struct {
+	clients map[string]*Client
+}
+
+// Names returns sorted names of the accounts.
+func (m *MultiClient) Names() []string {
+	return slices.Sorted(maps.Keys(m.clients))
+}
+
+// Client returns client of the named account or nil if there is no such account.
+func (m *MultiClient) Client(name string) *Client {
+	return m.clients[name]
+}
+
+// AccountError represents error of a single account.
+type AccountError struct {
+	Name string
+	Err  error
+}
+
+func (e *AccountError) Error() string {
+	return fmt.Sprintf("account %v: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *AccountError) Unwrap() error {
+	return e.Err
+}
+
+// Each calls fn concurrently for every account, errors are returned joined as *AccountError.
+func (m *MultiClient) Each(ctx context.Context, fn func(ctx context.Context, name string, c *Client) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, name := range m.Names() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(ctx, name, m.clients[name]); err != nil {
+				mu.Lock()
+				errs = append(errs, &AccountError{Name: name, Err: err})
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// ErrDuplicateAccount is returned by MultiClient when several names resolve to the same account.
+var ErrDuplicateAccount = errors.New("duplicate account")
+
+// ByPeriod returns transactions in date period of all the accounts keyed by their
+// name, account id and IBAN are available in the response Info. Responses of the
+// successful accounts are returned even if other accounts fail, errors are returned
+// joined as *AccountError. When several names resolve to the same account only the
+// first name in sorted order is returned, the others fail with ErrDuplicateAccount.
+func (m *MultiClient) ByPeriod(ctx context.Context, opts ByPeriodOptions) (map[string]*TransactionsResponse, error) {
+	var mu sync.Mutex
+	resps := make(map[string]*TransactionsResponse, len(m.clients))
+	err := m.Each(ctx, func(ctx context.Context, name string, c *Client) error {
+		resp, err := c.Transactions.ByPeriod(ctx, opts)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		resps[name] = resp
+		return nil
+	})
+
+	errs := []error{err}
+	seen := make(map[string]string, len(resps))
+	for _, name := range slices.Sorted(maps.Keys(resps)) {
+		key := accountKey(resps[name].Info)
+		if first, ok := seen[key]; ok {
+			delete(resps, name)
+			errs = append(errs, &AccountError{Name: name, Err: fmt.Errorf("%w: same as %v", ErrDuplicateAccount, first)})
+			continue
+		}
+		seen[key] = name
+	}
+	return resps, errors.Join(errs...)
+}
+
+// accountKey identifies account by its IBAN, or account id when IBAN is missing.
+func accountKey(info StatementInfo) string {
+	if info.IBAN != "" {
+		return info.IBAN
+	}
+	return strconv.FormatInt(info.AccountID, 10)
+}
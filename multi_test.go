@@ -0,0 +1,108 @@
+package fio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiClientByPeriod(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dateFrom := time.Now()
+	dateTo := time.Now()
+	for _, token := range []string{"first", "second"} {
+		urlStr := fmt.Sprintf("/v1/rest/periods/%v/%v/%v/transactions.xml", token, fmtDate(dateFrom), fmtDate(dateTo))
+		mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+			if token == "second" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			fmt.Fprint(w, transactionsResponse)
+		})
+	}
+
+	multi := NewMultiClient(map[string]string{
+		"main":    "first",
+		"savings": "second",
+	}, nil)
+	u, _ := url.Parse(server.URL)
+	for _, name := range multi.Names() {
+		multi.Client(name).BaseURL = u
+	}
+
+	opts := ByPeriodOptions{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+	resps, err := multi.ByPeriod(context.Background(), opts)
+
+	require.ErrorIs(t, err, ErrRateLimited)
+
+	var accErr *AccountError
+	require.ErrorAs(t, err, &accErr)
+	require.Equal(t, "savings", accErr.Name)
+
+	want, perr := parseTransactionsResponse(strings.NewReader(transactionsResponse), ParseOptions{})
+	require.NoError(t, perr)
+	require.Len(t, resps, 1)
+	assertEqualTransactionsResp(t, want, resps["main"])
+}
+
+func TestMultiClientByPeriodDuplicateAccount(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dateFrom := time.Now()
+	dateTo := time.Now()
+	for _, token := range []string{"first", "second"} {
+		urlStr := fmt.Sprintf("/v1/rest/periods/%v/%v/%v/transactions.xml", token, fmtDate(dateFrom), fmtDate(dateTo))
+		mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, transactionsResponse)
+		})
+	}
+
+	multi := NewMultiClient(map[string]string{
+		"main":  "first",
+		"other": "second",
+	}, nil)
+	u, _ := url.Parse(server.URL)
+	for _, name := range multi.Names() {
+		multi.Client(name).BaseURL = u
+	}
+
+	resps, err := multi.ByPeriod(context.Background(), ByPeriodOptions{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	})
+	require.ErrorIs(t, err, ErrDuplicateAccount)
+
+	var accErr *AccountError
+	require.ErrorAs(t, err, &accErr)
+	require.Equal(t, "other", accErr.Name)
+
+	require.Len(t, resps, 1)
+	require.Contains(t, resps, "main")
+}
+
+func TestMultiClientNames(t *testing.T) {
+	multi := NewMultiClient(map[string]string{
+		"b": "second",
+		"a": "first",
+	}, nil)
+
+	require.Equal(t, []string{"a", "b"}, multi.Names())
+	require.Equal(t, "first", multi.Client("a").Token)
+	require.Same(t, multi.Client("a").Limiter, multi.Client("b").Limiter)
+	require.Nil(t, multi.Client("c"))
+}
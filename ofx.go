@@ -0,0 +1,204 @@
+package fio
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jbub/fio/internal/gpc"
+)
+
+// ParseOFX parses statement exported in OFXFormat, both OFX 1.x SGML and OFX 2.x XML
+// documents are supported. OFX does not carry the opening balance, it is computed from
+// the closing balance and the transactions.
+func ParseOFX(r io.Reader) (*TransactionsResponse, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := string(data)
+	start := strings.Index(doc, "<OFX>")
+	if start < 0 {
+		return nil, errors.New("missing OFX element")
+	}
+	header, body := doc[:start], doc[start:]
+	if isWindows1250Header(header) {
+		body = gpc.DecodeWindows1250(body)
+	}
+
+	p := &ofxParser{resp: new(TransactionsResponse)}
+	if err := p.parse(body); err != nil {
+		return nil, err
+	}
+
+	resp := p.resp
+	resp.Info.OpeningBalance = resp.Info.ClosingBalance
+	for i, tx := range resp.Transactions {
+		resp.Info.OpeningBalance = resp.Info.OpeningBalance.Sub(tx.Amount)
+		resp.Transactions[i].Currency = resp.Info.Currency
+	}
+	if len(resp.Transactions) > 0 {
+		resp.Info.IDFrom = resp.Transactions[0].ID
+		resp.Info.IDTo = resp.Transactions[len(resp.Transactions)-1].ID
+	}
+	return resp, nil
+}
+
+// isWindows1250Header reports whether OFX header declares Windows-1250 charset.
+func isWindows1250Header(header string) bool {
+	header = strings.ToUpper(header)
+	return strings.Contains(header, "CHARSET:1250") || strings.Contains(header, "WINDOWS-1250")
+}
+
+// ofxParser walks OFX elements, leaf elements of OFX 1.x SGML are not closed.
+type ofxParser struct {
+	resp  *TransactionsResponse
+	stack []string
+	tx    *Transaction
+}
+
+func (p *ofxParser) parse(body string) error {
+	// closedLeaf is the leaf element whose value was just read, OFX 2.x closes it explicitly
+	var closedLeaf string
+	for body != "" {
+		open := strings.IndexByte(body, '<')
+		if open < 0 {
+			break
+		}
+		if text := strings.TrimSpace(body[:open]); text != "" && len(p.stack) > 0 {
+			name := p.stack[len(p.stack)-1]
+			p.stack = p.stack[:len(p.stack)-1]
+			if err := p.value(name, html.UnescapeString(text)); err != nil {
+				return err
+			}
+			closedLeaf = name
+		}
+
+		end := strings.IndexByte(body[open:], '>')
+		if end < 0 {
+			return errors.New("unterminated OFX element")
+		}
+		tag := body[open+1 : open+end]
+		body = body[open+end+1:]
+
+		switch {
+		case strings.HasSuffix(tag, "/"), strings.HasPrefix(tag, "?"), strings.HasPrefix(tag, "!"):
+		case strings.HasPrefix(tag, "/"):
+			name := tag[1:]
+			if name == closedLeaf {
+				closedLeaf = ""
+				continue
+			}
+			closedLeaf = ""
+			if err := p.end(name); err != nil {
+				return err
+			}
+		default:
+			closedLeaf = ""
+			p.start(tag)
+		}
+	}
+	if p.tx != nil || len(p.stack) > 0 {
+		return errors.New("unexpected end of OFX document")
+	}
+	return nil
+}
+
+func (p *ofxParser) start(name string) {
+	p.stack = append(p.stack, name)
+	if name == "STMTTRN" {
+		p.tx = new(Transaction)
+	}
+}
+
+func (p *ofxParser) end(name string) error {
+	for len(p.stack) > 0 {
+		top := p.stack[len(p.stack)-1]
+		p.stack = p.stack[:len(p.stack)-1]
+		if top == "STMTTRN" && p.tx != nil {
+			p.resp.Transactions = append(p.resp.Transactions, *p.tx)
+			p.tx = nil
+		}
+		if top == name {
+			return nil
+		}
+	}
+	return fmt.Errorf(`unexpected closing element: "%v"`, name)
+}
+
+func (p *ofxParser) parent() string {
+	if len(p.stack) == 0 {
+		return ""
+	}
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *ofxParser) value(name string, v string) (err error) {
+	info := &p.resp.Info
+	if p.tx != nil {
+		switch name {
+		case "FITID":
+			p.tx.ID, err = parseInteger(v)
+		case "DTPOSTED":
+			p.tx.Date, err = parseOFXTime(v)
+		case "TRNAMT":
+			p.tx.Amount, err = parseAmount(strings.Replace(v, ",", ".", 1))
+		case "NAME":
+			p.tx.AccountName = v
+		case "MEMO":
+			p.tx.RecipientMessage = v
+		}
+	} else {
+		switch parent := p.parent(); {
+		case name == "CURDEF":
+			info.Currency = v
+		case parent == "BANKACCTFROM" && name == "BANKID":
+			info.BankID = v
+		case parent == "BANKACCTFROM" && name == "ACCTID":
+			info.AccountID, err = parseOptionalInteger(v)
+		case parent == "BANKTRANLIST" && name == "DTSTART":
+			info.DateStart, err = parseOFXTime(v)
+		case parent == "BANKTRANLIST" && name == "DTEND":
+			info.DateEnd, err = parseOFXTime(v)
+		case parent == "LEDGERBAL" && name == "BALAMT":
+			info.ClosingBalance, err = parseAmount(strings.Replace(v, ",", ".", 1))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf(`unable to parse element: "%v": %w`, name, err)
+	}
+	return nil
+}
+
+// parseOFXTime parses OFX datetime in format YYYYMMDD[HHMMSS[.XXX]][[gmt offset[:tz name]]],
+// the time is in GMT when the offset is missing.
+func parseOFXTime(s string) (time.Time, error) {
+	loc := xmlGMTLocation
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		zone := strings.TrimSuffix(s[i+1:], "]")
+		offset, name, _ := strings.Cut(zone, ":")
+		hours, err := strconv.ParseFloat(offset, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc = time.FixedZone(name, int(hours*3600))
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s = s[:i]
+	}
+
+	switch len(s) {
+	case 8:
+		return time.ParseInLocation("20060102", s, loc)
+	case 12:
+		return time.ParseInLocation("200601021504", s, loc)
+	default:
+		return time.ParseInLocation("20060102150405", s, loc)
+	}
+}
@@ -0,0 +1,190 @@
+package fio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ofxSGMLStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:UTF-8
+CHARSET:NONE
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<SIGNONMSGSRSV1>
+<SONRS>
+<STATUS>
+<CODE>0
+<SEVERITY>INFO
+</STATUS>
+<DTSERVER>20240301120000
+<LANGUAGE>CES
+</SONRS>
+</SIGNONMSGSRSV1>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<TRNUID>0
+<STATUS>
+<CODE>0
+<SEVERITY>INFO
+</STATUS>
+<STMTRS>
+<CURDEF>CZK
+<BANKACCTFROM>
+<BANKID>2010
+<ACCTID>1234562
+<ACCTTYPE>CHECKING
+</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20240201
+<DTEND>20240229
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240210
+<TRNAMT>-20.00
+<FITID>26000000001
+<NAME>Žluťoučký kůň
+<MEMO>nájem &amp; služby
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240215120000[+1:CET]
+<TRNAMT>1.50
+<FITID>26000000002
+</STMTTRN>
+</BANKTRANLIST>
+<LEDGERBAL>
+<BALAMT>81.50
+<DTASOF>20240229
+</LEDGERBAL>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`
+
+const ofxXMLStatement = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <BANKMSGSRSV1>
+    <STMTTRNRS>
+      <TRNUID>0</TRNUID>
+      <STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+      <STMTRS>
+        <CURDEF>CZK</CURDEF>
+        <BANKACCTFROM>
+          <BANKID>2010</BANKID>
+          <ACCTID>1234562</ACCTID>
+          <ACCTTYPE>CHECKING</ACCTTYPE>
+        </BANKACCTFROM>
+        <BANKTRANLIST>
+          <DTSTART>20240201</DTSTART>
+          <DTEND>20240229</DTEND>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20240210</DTPOSTED>
+            <TRNAMT>-20.00</TRNAMT>
+            <FITID>26000000001</FITID>
+            <NAME>Žluťoučký kůň</NAME>
+            <MEMO>nájem &amp; služby</MEMO>
+          </STMTTRN>
+          <STMTTRN>
+            <TRNTYPE>CREDIT</TRNTYPE>
+            <DTPOSTED>20240215120000[+1:CET]</DTPOSTED>
+            <TRNAMT>1.50</TRNAMT>
+            <FITID>26000000002</FITID>
+          </STMTTRN>
+        </BANKTRANLIST>
+        <LEDGERBAL>
+          <BALAMT>81.50</BALAMT>
+          <DTASOF>20240229</DTASOF>
+        </LEDGERBAL>
+      </STMTRS>
+    </STMTTRNRS>
+  </BANKMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{name: "sgml", data: ofxSGMLStatement},
+		{name: "xml", data: ofxXMLStatement},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			resp, err := ParseOFX(strings.NewReader(cs.data))
+			require.NoError(t, err)
+
+			info := resp.Info
+			require.Equal(t, int64(1234562), info.AccountID)
+			require.Equal(t, "2010", info.BankID)
+			require.Equal(t, "CZK", info.Currency)
+			require.Equal(t, "100.00", info.OpeningBalance.StringFixed(2))
+			require.Equal(t, "81.50", info.ClosingBalance.StringFixed(2))
+			require.Equal(t, "2024-02-01", info.DateStart.Format("2006-01-02"))
+			require.Equal(t, "2024-02-29", info.DateEnd.Format("2006-01-02"))
+			require.Equal(t, int64(26000000001), info.IDFrom)
+			require.Equal(t, int64(26000000002), info.IDTo)
+
+			require.Len(t, resp.Transactions, 2)
+
+			tx := resp.Transactions[0]
+			require.Equal(t, int64(26000000001), tx.ID)
+			require.Equal(t, "2024-02-10", tx.Date.Format("2006-01-02"))
+			require.Equal(t, "-20.00", tx.Amount.StringFixed(2))
+			require.Equal(t, "CZK", tx.Currency)
+			require.Equal(t, "Žluťoučký kůň", tx.AccountName)
+			require.Equal(t, "nájem & služby", tx.RecipientMessage)
+
+			tx = resp.Transactions[1]
+			require.Equal(t, int64(26000000002), tx.ID)
+			require.Equal(t, "2024-02-15T11:00:00Z", tx.Date.UTC().Format(time.RFC3339))
+			require.Equal(t, "1.50", tx.Amount.StringFixed(2))
+			require.Equal(t, "", tx.AccountName)
+		})
+	}
+}
+
+func TestParseOFXWindows1250(t *testing.T) {
+	data := strings.Replace(ofxSGMLStatement, "CHARSET:NONE", "CHARSET:1250", 1)
+	data = strings.Replace(data, "Žluťoučký kůň", "\x8elu\x9dou\xe8k\xfd k\xf9\xf2", 1)
+	data = strings.Replace(data, "nájem", "n\xe1jem", 1)
+	data = strings.Replace(data, "služby", "slu\x9eby", 1)
+
+	resp, err := ParseOFX(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, "Žluťoučký kůň", resp.Transactions[0].AccountName)
+	require.Equal(t, "nájem & služby", resp.Transactions[0].RecipientMessage)
+}
+
+func TestParseOFXErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{name: "missing ofx", data: "OFXHEADER:100\n"},
+		{name: "truncated", data: ofxSGMLStatement[:strings.Index(ofxSGMLStatement, "</BANKTRANLIST>")]},
+		{name: "invalid amount", data: strings.Replace(ofxSGMLStatement, "-20.00", "abc", 1)},
+		{name: "invalid date", data: strings.Replace(ofxSGMLStatement, "<DTPOSTED>20240210", "<DTPOSTED>2024-02-10", 1)},
+		{name: "unexpected closing", data: strings.Replace(ofxSGMLStatement, "</OFX>", "</OFX>\n</STMTRS>", 1)},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			_, err := ParseOFX(strings.NewReader(cs.data))
+			require.Error(t, err)
+		})
+	}
+}
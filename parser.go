@@ -1,10 +1,15 @@
 package fio
 
 import (
+	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -33,7 +38,15 @@ const (
 	fieldAuthor             = "9"  // Provedl
 	fieldPayerReference     = "27" // Reference plátce
 
-	xmlTimeFormat = "2006-01-02-07:00"
+	fieldMerchantTerminalID     = "28" // ID terminálu
+	fieldMerchantCardType       = "29" // Typ karty
+	fieldMerchantCardNumber     = "30" // Číslo karty
+	fieldMerchantFee            = "31" // Poplatek
+	fieldMerchantNetAmount      = "32" // Částka po poplatku
+	fieldMerchantSettlementDate = "33" // Datum zúčtování
+
+	xmlTimeFormat  = "2006-01-02-07:00"
+	jsonTimeFormat = "2006-01-02-0700"
 )
 
 var (
@@ -60,6 +73,36 @@ type xmlErrorResult struct {
 	Detail    string `xml:"detail"`
 }
 
+type xmlImportResponse struct {
+	XMLName xml.Name          `xml:"responseImport"`
+	Result  xmlImportResult   `xml:"result"`
+	Details []xmlImportDetail `xml:"ordersDetails>detail"`
+}
+
+type xmlImportResult struct {
+	ErrorCode     int            `xml:"errorCode"`
+	InstructionID string         `xml:"idInstruction"`
+	Status        string         `xml:"status"`
+	Sums          []xmlImportSum `xml:"sums>sum"`
+}
+
+type xmlImportSum struct {
+	Currency string     `xml:"id,attr"`
+	Credit   xmlDecimal `xml:"sumCredit"`
+	Debit    xmlDecimal `xml:"sumDebit"`
+}
+
+type xmlImportDetail struct {
+	ID       int                `xml:"id,attr"`
+	Messages []xmlImportMessage `xml:"messages>message"`
+}
+
+type xmlImportMessage struct {
+	Status    string `xml:"status,attr"`
+	ErrorCode int    `xml:"errorCode,attr"`
+	Message   string `xml:",chardata"`
+}
+
 type xmlTransactionsResponse struct {
 	XMLName      xml.Name          `xml:"AccountStatement"`
 	Info         xmlStatementInfo  `xml:"Info"`
@@ -93,6 +136,14 @@ type xmlTransactionColumn struct {
 	Value string `xml:",chardata"`
 }
 
+func (t xmlTtransaction) columns() []Column {
+	cols := make([]Column, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		cols = append(cols, Column(col))
+	}
+	return cols
+}
+
 type xmlTime struct {
 	time.Time
 }
@@ -131,7 +182,7 @@ func (d *xmlDecimal) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) erro
 	return nil
 }
 
-func parseTransactionsResponse(r io.Reader) (*TransactionsResponse, error) {
+func parseTransactionsResponse(r io.Reader, opts ParseOptions) (*TransactionsResponse, error) {
 	var xmlResp xmlTransactionsResponse
 	enc := xml.NewDecoder(r)
 	if err := enc.Decode(&xmlResp); err != nil {
@@ -139,25 +190,10 @@ func parseTransactionsResponse(r io.Reader) (*TransactionsResponse, error) {
 	}
 
 	resp := new(TransactionsResponse)
-	resp.Info = StatementInfo{
-		AccountID:      xmlResp.Info.AccountID,
-		BankID:         xmlResp.Info.BankID,
-		Currency:       xmlResp.Info.Currency,
-		IBAN:           xmlResp.Info.IBAN,
-		BIC:            xmlResp.Info.BIC,
-		OpeningBalance: xmlResp.Info.OpeningBalance.Decimal,
-		ClosingBalance: xmlResp.Info.ClosingBalance.Decimal,
-		DateStart:      xmlResp.Info.DateStart.Time,
-		DateEnd:        xmlResp.Info.DateEnd.Time,
-		YearList:       xmlResp.Info.YearList,
-		IDList:         xmlResp.Info.IDList,
-		IDFrom:         xmlResp.Info.IDFrom,
-		IDTo:           xmlResp.Info.IDTo,
-		IDLastDownload: xmlResp.Info.IDLastDownload,
-	}
+	resp.Info = newStatementInfo(xmlResp.Info)
 
 	for _, xmlTx := range xmlResp.Transactions {
-		tx, err := parseTransaction(xmlTx)
+		tx, err := parseTransaction(xmlTx.columns(), opts)
 		if err != nil {
 			return nil, err
 		}
@@ -166,66 +202,421 @@ func parseTransactionsResponse(r io.Reader) (*TransactionsResponse, error) {
 	return resp, nil
 }
 
-func parseTransaction(t xmlTtransaction) (*Transaction, error) {
+type jsonTransactionsResponse struct {
+	AccountStatement struct {
+		Info            jsonStatementInfo `json:"info"`
+		TransactionList struct {
+			Transactions []jsonTransaction `json:"transaction"`
+		} `json:"transactionList"`
+	} `json:"accountStatement"`
+}
+
+type jsonStatementInfo struct {
+	AccountID      jsonValue `json:"accountId"`
+	BankID         jsonValue `json:"bankId"`
+	Currency       jsonValue `json:"currency"`
+	IBAN           jsonValue `json:"iban"`
+	BIC            jsonValue `json:"bic"`
+	OpeningBalance jsonValue `json:"openingBalance"`
+	ClosingBalance jsonValue `json:"closingBalance"`
+	DateStart      jsonValue `json:"dateStart"`
+	DateEnd        jsonValue `json:"dateEnd"`
+	YearList       jsonValue `json:"yearList"`
+	IDList         jsonValue `json:"idList"`
+	IDFrom         jsonValue `json:"idFrom"`
+	IDTo           jsonValue `json:"idTo"`
+	IDLastDownload jsonValue `json:"idLastDownload"`
+}
+
+type jsonTransaction map[string]*jsonTransactionColumn
+
+type jsonTransactionColumn struct {
+	ID    jsonValue `json:"id"`
+	Name  string    `json:"name"`
+	Value jsonValue `json:"value"`
+}
+
+func (t jsonTransaction) columns() []Column {
+	cols := make([]Column, 0, len(t))
+	for _, key := range slices.Sorted(maps.Keys(t)) {
+		col := t[key]
+		if col == nil {
+			continue
+		}
+		cols = append(cols, Column{
+			ID:    string(col.ID),
+			Name:  col.Name,
+			Value: string(col.Value),
+		})
+	}
+	return cols
+}
+
+// jsonValue holds string, number or null json value as a string.
+type jsonValue string
+
+func (v *jsonValue) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*v = jsonValue(s)
+		return nil
+	}
+	*v = jsonValue(data)
+	return nil
+}
+
+func parseJSONTransactionsResponse(r io.Reader, opts ParseOptions) (*TransactionsResponse, error) {
+	var jsonResp jsonTransactionsResponse
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&jsonResp); err != nil {
+		return nil, err
+	}
+
+	info, err := newJSONStatementInfo(jsonResp.AccountStatement.Info)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TransactionsResponse{Info: *info}
+	for _, jsonTx := range jsonResp.AccountStatement.TransactionList.Transactions {
+		tx, err := parseTransaction(jsonTx.columns(), opts)
+		if err != nil {
+			return nil, err
+		}
+		resp.Transactions = append(resp.Transactions, *tx)
+	}
+	return resp, nil
+}
+
+func newJSONStatementInfo(info jsonStatementInfo) (*StatementInfo, error) {
+	var err error
+	si := &StatementInfo{
+		BankID:   string(info.BankID),
+		Currency: string(info.Currency),
+		IBAN:     string(info.IBAN),
+		BIC:      string(info.BIC),
+	}
+	if si.AccountID, err = parseOptionalInteger(string(info.AccountID)); err != nil {
+		return nil, err
+	}
+	if si.OpeningBalance, err = parseAmount(string(info.OpeningBalance)); err != nil {
+		return nil, err
+	}
+	if si.ClosingBalance, err = parseAmount(string(info.ClosingBalance)); err != nil {
+		return nil, err
+	}
+	if si.DateStart, err = parseGMTTime(string(info.DateStart)); err != nil {
+		return nil, err
+	}
+	if si.DateEnd, err = parseGMTTime(string(info.DateEnd)); err != nil {
+		return nil, err
+	}
+	if si.YearList, err = parseOptionalInteger(string(info.YearList)); err != nil {
+		return nil, err
+	}
+	if si.IDList, err = parseOptionalInteger(string(info.IDList)); err != nil {
+		return nil, err
+	}
+	if si.IDFrom, err = parseOptionalInteger(string(info.IDFrom)); err != nil {
+		return nil, err
+	}
+	if si.IDTo, err = parseOptionalInteger(string(info.IDTo)); err != nil {
+		return nil, err
+	}
+	if si.IDLastDownload, err = parseOptionalInteger(string(info.IDLastDownload)); err != nil {
+		return nil, err
+	}
+	return si, nil
+}
+
+func newStatementInfo(info xmlStatementInfo) StatementInfo {
+	return StatementInfo{
+		AccountID:      info.AccountID,
+		BankID:         info.BankID,
+		Currency:       info.Currency,
+		IBAN:           info.IBAN,
+		BIC:            info.BIC,
+		OpeningBalance: info.OpeningBalance.Decimal,
+		ClosingBalance: info.ClosingBalance.Decimal,
+		DateStart:      info.DateStart.Time,
+		DateEnd:        info.DateEnd.Time,
+		YearList:       info.YearList,
+		IDList:         info.IDList,
+		IDFrom:         info.IDFrom,
+		IDTo:           info.IDTo,
+		IDLastDownload: info.IDLastDownload,
+	}
+}
+
+// newTransactionStream reads the statement info and leaves the decoder
+// positioned before the first transaction.
+func newTransactionStream(r io.ReadCloser, opts ParseOptions) (*TransactionStream, error) {
+	dec := xml.NewDecoder(r)
+	root := true
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, errors.New("missing statement info")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root {
+			if start.Name.Local != "AccountStatement" {
+				return nil, fmt.Errorf(`unexpected root element: "%v"`, start.Name.Local)
+			}
+			root = false
+			continue
+		}
+		if start.Name.Local != "Info" {
+			return nil, fmt.Errorf(`unexpected element: "%v"`, start.Name.Local)
+		}
+
+		var info xmlStatementInfo
+		if err := dec.DecodeElement(&info, &start); err != nil {
+			return nil, err
+		}
+		return &TransactionStream{
+			Info: newStatementInfo(info),
+			body: r,
+			dec:  dec,
+			opts: opts,
+		}, nil
+	}
+}
+
+func (s *TransactionStream) next() (*Transaction, error) {
+	for {
+		tok, err := s.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Transaction" {
+			continue
+		}
+
+		var xmlTx xmlTtransaction
+		if err := s.dec.DecodeElement(&xmlTx, &start); err != nil {
+			return nil, err
+		}
+		return parseTransaction(xmlTx.columns(), s.opts)
+	}
+}
+
+func parseLastStatementResponse(r io.Reader) (*LastStatementResponse, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	year, id, ok := strings.Cut(strings.TrimSpace(string(data)), ",")
+	if !ok {
+		return nil, fmt.Errorf(`unable to parse last statement: "%v"`, data)
+	}
+
+	resp := new(LastStatementResponse)
+	if resp.Year, err = strconv.Atoi(year); err != nil {
+		return nil, err
+	}
+	if resp.ID, err = strconv.Atoi(id); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func parseImportResponse(r io.Reader) (*ImportResult, error) {
+	var xmlResp xmlImportResponse
+	dec := xml.NewDecoder(r)
+	if err := dec.Decode(&xmlResp); err != nil {
+		return nil, err
+	}
+	return newImportResult(xmlResp), nil
+}
+
+func newImportResult(xmlResp xmlImportResponse) *ImportResult {
+	resp := &ImportResult{
+		ErrorCode:     xmlResp.Result.ErrorCode,
+		InstructionID: xmlResp.Result.InstructionID,
+		Status:        ImportStatus(xmlResp.Result.Status),
+	}
+	for _, xmlSum := range xmlResp.Result.Sums {
+		resp.Sums = append(resp.Sums, ImportSum{
+			Currency: xmlSum.Currency,
+			Credit:   xmlSum.Credit.Decimal,
+			Debit:    xmlSum.Debit.Decimal,
+		})
+	}
+	for _, xmlDetail := range xmlResp.Details {
+		detail := ImportDetail{ID: xmlDetail.ID}
+		for _, xmlMsg := range xmlDetail.Messages {
+			detail.Messages = append(detail.Messages, ImportMessage{
+				Status:    ImportStatus(xmlMsg.Status),
+				ErrorCode: xmlMsg.ErrorCode,
+				Message:   xmlMsg.Message,
+			})
+		}
+		resp.Details = append(resp.Details, detail)
+	}
+	return resp
+}
+
+// Column represents raw statement column.
+type Column struct {
+	ID    string
+	Name  string
+	Value string
+}
+
+// ParseOptions configures parsing of the statements.
+type ParseOptions struct {
+	// Lenient keeps unknown columns in Transaction.Extra instead of
+	// failing, strict mode is the default.
+	Lenient bool
+
+	// OnUnknownColumn is called for every unknown column kept in lenient mode.
+	OnUnknownColumn func(col Column)
+}
+
+// columnSpec describes how statement column is mapped to transaction field,
+// it is shared by all the statement formats.
+type columnSpec[T any] struct {
+	name  string
+	parse func(tx *T, v string) error
+}
+
+var transactionColumns = map[string]columnSpec[Transaction]{
+	fieldTransactionID: {name: "ID pohybu", parse: func(tx *Transaction, v string) (err error) {
+		tx.ID, err = parseInteger(v)
+		return err
+	}},
+	fieldDate: {name: "Datum", parse: func(tx *Transaction, v string) (err error) {
+		tx.Date, err = parseGMTTime(v)
+		return err
+	}},
+	fieldAmount: {name: "Objem", parse: func(tx *Transaction, v string) (err error) {
+		tx.Amount, err = parseAmount(v)
+		return err
+	}},
+	fieldCurrency:           {name: "Měna", parse: stringColumn(func(tx *Transaction) *string { return &tx.Currency })},
+	fieldAccount:            {name: "Protiúčet", parse: stringColumn(func(tx *Transaction) *string { return &tx.Account })},
+	fieldAccountName:        {name: "Název protiúčtu", parse: stringColumn(func(tx *Transaction) *string { return &tx.AccountName })},
+	fieldBankCode:           {name: "Kód banky", parse: stringColumn(func(tx *Transaction) *string { return &tx.BankCode })},
+	fieldBankName:           {name: "Název banky", parse: stringColumn(func(tx *Transaction) *string { return &tx.BankName })},
+	fieldConstantSymbol:     {name: "KS", parse: stringColumn(func(tx *Transaction) *string { return &tx.ConstantSymbol })},
+	fieldVariableSymbol:     {name: "VS", parse: stringColumn(func(tx *Transaction) *string { return &tx.VariableSymbol })},
+	fieldSpecificSymbol:     {name: "SS", parse: stringColumn(func(tx *Transaction) *string { return &tx.SpecificSymbol })},
+	fieldUserIdentification: {name: "Uživatelská identifikace", parse: stringColumn(func(tx *Transaction) *string { return &tx.UserIdentification })},
+	fieldRecipientMessage:   {name: "Zpráva pro příjemce", parse: stringColumn(func(tx *Transaction) *string { return &tx.RecipientMessage })},
+	fieldType:               {name: "Typ", parse: stringColumn(func(tx *Transaction) *string { return &tx.Type })},
+	fieldSpecification:      {name: "Upřesnění", parse: stringColumn(func(tx *Transaction) *string { return &tx.Specification })},
+	fieldComment:            {name: "Komentář", parse: stringColumn(func(tx *Transaction) *string { return &tx.Comment })},
+	fieldBIC:                {name: "BIC", parse: stringColumn(func(tx *Transaction) *string { return &tx.BIC })},
+	fieldOrderID:            {name: "ID pokynu", parse: stringColumn(func(tx *Transaction) *string { return &tx.OrderID })},
+	fieldAuthor:             {name: "Provedl", parse: stringColumn(func(tx *Transaction) *string { return &tx.Author })},
+	fieldPayerReference:     {name: "Reference plátce", parse: stringColumn(func(tx *Transaction) *string { return &tx.PayerReference })},
+}
+
+var merchantColumns = map[string]columnSpec[MerchantTransaction]{
+	fieldTransactionID: {name: "ID pohybu", parse: func(tx *MerchantTransaction, v string) (err error) {
+		tx.ID, err = parseInteger(v)
+		return err
+	}},
+	fieldDate: {name: "Datum", parse: func(tx *MerchantTransaction, v string) (err error) {
+		tx.Date, err = parseGMTTime(v)
+		return err
+	}},
+	fieldAmount: {name: "Objem", parse: func(tx *MerchantTransaction, v string) (err error) {
+		tx.GrossAmount, err = parseAmount(v)
+		return err
+	}},
+	fieldCurrency:           {name: "Měna", parse: stringColumn(func(tx *MerchantTransaction) *string { return &tx.Currency })},
+	fieldMerchantTerminalID: {name: "ID terminálu", parse: stringColumn(func(tx *MerchantTransaction) *string { return &tx.TerminalID })},
+	fieldMerchantCardType:   {name: "Typ karty", parse: stringColumn(func(tx *MerchantTransaction) *string { return &tx.CardType })},
+	fieldMerchantCardNumber: {name: "Číslo karty", parse: stringColumn(func(tx *MerchantTransaction) *string { return &tx.CardNumber })},
+	fieldMerchantFee: {name: "Poplatek", parse: func(tx *MerchantTransaction, v string) (err error) {
+		tx.Fee, err = parseAmount(v)
+		return err
+	}},
+	fieldMerchantNetAmount: {name: "Částka po poplatku", parse: func(tx *MerchantTransaction, v string) (err error) {
+		tx.NetAmount, err = parseAmount(v)
+		return err
+	}},
+	fieldMerchantSettlementDate: {name: "Datum zúčtování", parse: func(tx *MerchantTransaction, v string) (err error) {
+		tx.SettlementDate, err = parseGMTTime(v)
+		return err
+	}},
+}
+
+func stringColumn[T any](field func(tx *T) *string) func(tx *T, v string) error {
+	return func(tx *T, v string) error {
+		*field(tx) = v
+		return nil
+	}
+}
+
+func parseTransaction(cols []Column, opts ParseOptions) (*Transaction, error) {
 	tx := new(Transaction)
-	for _, col := range t.Columns {
-		switch col.ID {
-		case fieldTransactionID:
-			v, err := parseInteger(col.Value)
-			if err != nil {
-				return nil, err
+	if err := parseColumns(tx, &tx.Extra, transactionColumns, cols, opts); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func parseMerchantTransactionsResponse(r io.Reader, opts ParseOptions) (*MerchantTransactionsResponse, error) {
+	var xmlResp xmlTransactionsResponse
+	dec := xml.NewDecoder(r)
+	if err := dec.Decode(&xmlResp); err != nil {
+		return nil, err
+	}
+
+	resp := new(MerchantTransactionsResponse)
+	resp.Info = newStatementInfo(xmlResp.Info)
+
+	for _, xmlTx := range xmlResp.Transactions {
+		tx := new(MerchantTransaction)
+		if err := parseColumns(tx, &tx.Extra, merchantColumns, xmlTx.columns(), opts); err != nil {
+			return nil, err
+		}
+		resp.Transactions = append(resp.Transactions, *tx)
+	}
+	return resp, nil
+}
+
+func parseColumns[T any](tx *T, extra *map[string]Column, specs map[string]columnSpec[T], cols []Column, opts ParseOptions) error {
+	for _, col := range cols {
+		spec, ok := specs[col.ID]
+		if !ok {
+			if !opts.Lenient {
+				return fmt.Errorf(`unable to parse column: "%v"`, col.Name)
 			}
-			tx.ID = v
-		case fieldDate:
-			v, err := parseGMTTime(col.Value)
-			if err != nil {
-				return nil, err
+			if *extra == nil {
+				*extra = make(map[string]Column)
 			}
-			tx.Date = v
-		case fieldAmount:
-			v, err := parseAmount(col.Value)
-			if err != nil {
-				return nil, err
+			(*extra)[col.ID] = col
+			if opts.OnUnknownColumn != nil {
+				opts.OnUnknownColumn(col)
 			}
-			tx.Amount = v
-		case fieldCurrency:
-			tx.Currency = col.Value
-		case fieldAccount:
-			tx.Account = col.Value
-		case fieldBankCode:
-			tx.BankCode = col.Value
-		case fieldAccountName:
-			tx.AccountName = col.Value
-		case fieldBankName:
-			tx.BankName = col.Value
-		case fieldConstantSymbol:
-			tx.ConstantSymbol = col.Value
-		case fieldVariableSymbol:
-			tx.VariableSymbol = col.Value
-		case fieldSpecificSymbol:
-			tx.SpecificSymbol = col.Value
-		case fieldUserIdentification:
-			tx.UserIdentification = col.Value
-		case fieldRecipientMessage:
-			tx.RecipientMessage = col.Value
-		case fieldType:
-			tx.Type = col.Value
-		case fieldSpecification:
-			tx.Specification = col.Value
-		case fieldComment:
-			tx.Comment = col.Value
-		case fieldBIC:
-			tx.BIC = col.Value
-		case fieldOrderID:
-			tx.OrderID = col.Value
-		case fieldPayerReference:
-			tx.PayerReference = col.Value
-		case fieldAuthor:
-		default:
-			return nil, fmt.Errorf(`unable to parse column: "%v"`, col.Name)
+			continue
+		}
+		if err := spec.parse(tx, col.Value); err != nil {
+			return err
 		}
 	}
-	return tx, nil
+	return nil
 }
 
 func parseAmount(s string) (decimal.Decimal, error) {
@@ -236,6 +627,16 @@ func parseInteger(s string) (int64, error) {
 	return strconv.ParseInt(s, 10, 64)
 }
 
+func parseOptionalInteger(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return parseInteger(s)
+}
+
 func parseGMTTime(s string) (time.Time, error) {
+	if t, err := time.ParseInLocation(jsonTimeFormat, s, xmlGMTLocation); err == nil {
+		return t, nil
+	}
 	return time.ParseInLocation(xmlTimeFormat, s, xmlGMTLocation)
 }
@@ -0,0 +1,136 @@
+package fio
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+const unknownColumnTransactionsResponse = `
+<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<AccountStatement>
+  <Info>
+    <accountId>2501201133</accountId>
+    <bankId>8330</bankId>
+    <currency>EUR</currency>
+    <openingBalance>0.00</openingBalance>
+    <closingBalance>45.97</closingBalance>
+    <dateStart>2017-01-01+01:00</dateStart>
+    <dateEnd>2017-05-01+02:00</dateEnd>
+  </Info>
+  <TransactionList>
+    <Transaction>
+      <column_22 name="ID pohybu" id="22">13926601410</column_22>
+      <column_1 name="Objem" id="1">45.97</column_1>
+      <column_99 name="Nový sloupec" id="99">hodnota</column_99>
+    </Transaction>
+  </TransactionList>
+</AccountStatement>
+`
+
+func TestParseTransactionsStrict(t *testing.T) {
+	_, err := parseTransactionsResponse(strings.NewReader(unknownColumnTransactionsResponse), ParseOptions{})
+
+	require.EqualError(t, err, `unable to parse column: "Nový sloupec"`)
+}
+
+func TestParseTransactionsLenient(t *testing.T) {
+	var warnings []Column
+	opts := ParseOptions{
+		Lenient: true,
+		OnUnknownColumn: func(col Column) {
+			warnings = append(warnings, col)
+		},
+	}
+	resp, err := parseTransactionsResponse(strings.NewReader(unknownColumnTransactionsResponse), opts)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Transactions, 1)
+
+	want := Column{ID: "99", Name: "Nový sloupec", Value: "hodnota"}
+	tx := resp.Transactions[0]
+	require.Equal(t, int64(13926601410), tx.ID)
+	require.Equal(t, map[string]Column{"99": want}, tx.Extra)
+	require.Equal(t, []Column{want}, warnings)
+}
+
+const allColumnsTransactionsResponse = `
+<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<AccountStatement>
+  <Info>
+    <accountId>2000000000</accountId>
+    <bankId>2010</bankId>
+    <currency>CZK</currency>
+    <iban>CZ1220100000002000000000</iban>
+    <bic>FIOBCZPPXXX</bic>
+    <openingBalance>1000.00</openingBalance>
+    <closingBalance>900.00</closingBalance>
+    <dateStart>2023-03-01+01:00</dateStart>
+    <dateEnd>2023-03-31+02:00</dateEnd>
+    <idFrom>25000000001</idFrom>
+    <idTo>25000000001</idTo>
+  </Info>
+  <TransactionList>
+    <Transaction>
+      <column_22 name="ID pohybu" id="22">25000000001</column_22>
+      <column_0 name="Datum" id="0">2023-03-15+01:00</column_0>
+      <column_1 name="Objem" id="1">-100.00</column_1>
+      <column_14 name="Měna" id="14">CZK</column_14>
+      <column_2 name="Protiúčet" id="2">2212-2000000699</column_2>
+      <column_10 name="Název protiúčtu" id="10">Jan Novák</column_10>
+      <column_3 name="Kód banky" id="3">0300</column_3>
+      <column_12 name="Název banky" id="12">Československá obchodní banka, a.s.</column_12>
+      <column_4 name="KS" id="4">0308</column_4>
+      <column_5 name="VS" id="5">1234567890</column_5>
+      <column_6 name="SS" id="6">42</column_6>
+      <column_7 name="Uživatelská identifikace" id="7">Nájem březen</column_7>
+      <column_16 name="Zpráva pro příjemce" id="16">Nájem za březen 2023</column_16>
+      <column_8 name="Typ" id="8">Platba převodem uvnitř banky</column_8>
+      <column_9 name="Provedl" id="9">Novák, Jan</column_9>
+      <column_18 name="Upřesnění" id="18">100.00 CZK</column_18>
+      <column_25 name="Komentář" id="25">Nájem</column_25>
+      <column_26 name="BIC" id="26">CEKOCZPP</column_26>
+      <column_17 name="ID pokynu" id="17">30000000001</column_17>
+      <column_27 name="Reference plátce" id="27">INV-2023-03</column_27>
+    </Transaction>
+  </TransactionList>
+</AccountStatement>
+`
+
+func TestParseTransactionAllColumns(t *testing.T) {
+	for id, spec := range transactionColumns {
+		require.Contains(t, allColumnsTransactionsResponse, fmt.Sprintf(`name="%v" id="%v"`, spec.name, id))
+	}
+
+	resp, err := parseTransactionsResponse(strings.NewReader(allColumnsTransactionsResponse), ParseOptions{})
+	require.NoError(t, err)
+	require.Len(t, resp.Transactions, 1)
+
+	want := Transaction{
+		ID:                 25000000001,
+		Date:               time.Date(2023, time.March, 15, 0, 0, 0, 0, time.FixedZone("+0100", 60*60)),
+		Amount:             decimal.RequireFromString("-100.00"),
+		Currency:           "CZK",
+		Account:            "2212-2000000699",
+		AccountName:        "Jan Novák",
+		BankCode:           "0300",
+		BankName:           "Československá obchodní banka, a.s.",
+		ConstantSymbol:     "0308",
+		VariableSymbol:     "1234567890",
+		SpecificSymbol:     "42",
+		UserIdentification: "Nájem březen",
+		RecipientMessage:   "Nájem za březen 2023",
+		Type:               "Platba převodem uvnitř banky",
+		Author:             "Novák, Jan",
+		Specification:      "100.00 CZK",
+		Comment:            "Nájem",
+		BIC:                "CEKOCZPP",
+		OrderID:            "30000000001",
+		PayerReference:     "INV-2023-03",
+	}
+	assertEqualTransaction(t, want, resp.Transactions[0])
+}
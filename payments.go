@@ -0,0 +1,468 @@
+package fio
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	importSchemaLocation = "http://www.fio.cz/schema/importIB.xsd"
+	importXSINamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// PaymentType represents type of the payment order.
+type PaymentType string
+
+// Supported PaymentType values for domestic orders.
+const (
+	PaymentTypeStandard   PaymentType = "431001" // Standardní platba
+	PaymentTypeExpress    PaymentType = "431004" // Expresní platba
+	PaymentTypePriority   PaymentType = "431005" // Prioritní platba
+	PaymentTypeCollection PaymentType = "431022" // Příkaz k inkasu
+)
+
+// Supported PaymentType values for T2 (euro) orders.
+const (
+	PaymentTypeEuroStandard PaymentType = "431008" // Standardní platba
+	PaymentTypeEuroPriority PaymentType = "431009" // Prioritní platba
+)
+
+// ChargesType represents who pays the fees of the foreign payment order.
+type ChargesType string
+
+// Supported ChargesType values.
+const (
+	ChargesOur    ChargesType = "470501" // Všechny poplatky hradí plátce
+	ChargesBen    ChargesType = "470502" // Všechny poplatky hradí příjemce
+	ChargesShared ChargesType = "470503" // Každý hradí své poplatky
+)
+
+// Order represents payment order which can be imported using PaymentsService.
+type Order interface {
+	// Validate checks whether all the fields required by the order type are set.
+	Validate() error
+
+	xmlOrder() interface{}
+}
+
+// DomesticTransaction represents domestic (CZK) payment order.
+type DomesticTransaction struct {
+	AccountFrom      string
+	Currency         string
+	Amount           decimal.Decimal
+	AccountTo        string
+	BankCode         string
+	ConstantSymbol   string
+	VariableSymbol   string
+	SpecificSymbol   string
+	Date             time.Time
+	RecipientMessage string
+	Comment          string
+	PaymentReason    string
+	PaymentType      PaymentType
+}
+
+// Validate checks whether all the fields required by domestic order are set.
+func (t DomesticTransaction) Validate() error {
+	return errors.Join(
+		requiredString("accountFrom", t.AccountFrom),
+		requiredString("currency", t.Currency),
+		requiredAmount("amount", t.Amount),
+		requiredString("accountTo", t.AccountTo),
+		requiredString("bankCode", t.BankCode),
+		requiredDate("date", t.Date),
+	)
+}
+
+func (t DomesticTransaction) xmlOrder() interface{} {
+	return xmlDomesticTransaction{
+		AccountFrom:      t.AccountFrom,
+		Currency:         t.Currency,
+		Amount:           fmtAmount(t.Amount),
+		AccountTo:        t.AccountTo,
+		BankCode:         t.BankCode,
+		ConstantSymbol:   t.ConstantSymbol,
+		VariableSymbol:   t.VariableSymbol,
+		SpecificSymbol:   t.SpecificSymbol,
+		Date:             fmtDate(t.Date),
+		RecipientMessage: t.RecipientMessage,
+		Comment:          t.Comment,
+		PaymentReason:    t.PaymentReason,
+		PaymentType:      string(t.PaymentType),
+	}
+}
+
+// T2Transaction represents euro (T2/SEPA) payment order.
+type T2Transaction struct {
+	AccountFrom     string
+	Currency        string
+	Amount          decimal.Decimal
+	AccountTo       string
+	ConstantSymbol  string
+	VariableSymbol  string
+	SpecificSymbol  string
+	BIC             string
+	Date            time.Time
+	Comment         string
+	BenefName       string
+	BenefStreet     string
+	BenefCity       string
+	BenefCountry    string
+	RemittanceInfo1 string
+	RemittanceInfo2 string
+	RemittanceInfo3 string
+	RemittanceInfo4 string
+	PaymentReason   string
+	PaymentType     PaymentType
+}
+
+// Validate checks whether all the fields required by T2 order are set.
+func (t T2Transaction) Validate() error {
+	return errors.Join(
+		requiredString("accountFrom", t.AccountFrom),
+		requiredString("currency", t.Currency),
+		requiredAmount("amount", t.Amount),
+		requiredString("accountTo", t.AccountTo),
+		requiredDate("date", t.Date),
+		requiredString("benefName", t.BenefName),
+	)
+}
+
+func (t T2Transaction) xmlOrder() interface{} {
+	return xmlT2Transaction{
+		AccountFrom:     t.AccountFrom,
+		Currency:        t.Currency,
+		Amount:          fmtAmount(t.Amount),
+		AccountTo:       t.AccountTo,
+		ConstantSymbol:  t.ConstantSymbol,
+		VariableSymbol:  t.VariableSymbol,
+		SpecificSymbol:  t.SpecificSymbol,
+		BIC:             t.BIC,
+		Date:            fmtDate(t.Date),
+		Comment:         t.Comment,
+		BenefName:       t.BenefName,
+		BenefStreet:     t.BenefStreet,
+		BenefCity:       t.BenefCity,
+		BenefCountry:    t.BenefCountry,
+		RemittanceInfo1: t.RemittanceInfo1,
+		RemittanceInfo2: t.RemittanceInfo2,
+		RemittanceInfo3: t.RemittanceInfo3,
+		RemittanceInfo4: t.RemittanceInfo4,
+		PaymentReason:   t.PaymentReason,
+		PaymentType:     string(t.PaymentType),
+	}
+}
+
+// ForeignTransaction represents foreign (SWIFT) payment order.
+type ForeignTransaction struct {
+	AccountFrom      string
+	Currency         string
+	Amount           decimal.Decimal
+	AccountTo        string
+	BIC              string
+	Date             time.Time
+	Comment          string
+	BenefName        string
+	BenefStreet      string
+	BenefCity        string
+	BenefCountry     string
+	RemittanceInfo1  string
+	RemittanceInfo2  string
+	RemittanceInfo3  string
+	RemittanceInfo4  string
+	DetailsOfCharges ChargesType
+	PaymentReason    string
+}
+
+// Validate checks whether all the fields required by foreign order are set.
+func (t ForeignTransaction) Validate() error {
+	return errors.Join(
+		requiredString("accountFrom", t.AccountFrom),
+		requiredString("currency", t.Currency),
+		requiredAmount("amount", t.Amount),
+		requiredString("accountTo", t.AccountTo),
+		requiredString("bic", t.BIC),
+		requiredDate("date", t.Date),
+		requiredString("benefName", t.BenefName),
+		requiredString("benefStreet", t.BenefStreet),
+		requiredString("benefCity", t.BenefCity),
+		requiredString("benefCountry", t.BenefCountry),
+		requiredString("remittanceInfo1", t.RemittanceInfo1),
+		requiredString("detailsOfCharges", string(t.DetailsOfCharges)),
+		requiredString("paymentReason", t.PaymentReason),
+	)
+}
+
+func (t ForeignTransaction) xmlOrder() interface{} {
+	return xmlForeignTransaction{
+		AccountFrom:      t.AccountFrom,
+		Currency:         t.Currency,
+		Amount:           fmtAmount(t.Amount),
+		AccountTo:        t.AccountTo,
+		BIC:              t.BIC,
+		Date:             fmtDate(t.Date),
+		Comment:          t.Comment,
+		BenefName:        t.BenefName,
+		BenefStreet:      t.BenefStreet,
+		BenefCity:        t.BenefCity,
+		BenefCountry:     t.BenefCountry,
+		RemittanceInfo1:  t.RemittanceInfo1,
+		RemittanceInfo2:  t.RemittanceInfo2,
+		RemittanceInfo3:  t.RemittanceInfo3,
+		RemittanceInfo4:  t.RemittanceInfo4,
+		DetailsOfCharges: string(t.DetailsOfCharges),
+		PaymentReason:    t.PaymentReason,
+	}
+}
+
+type xmlImport struct {
+	XMLName        xml.Name      `xml:"Import"`
+	XSI            string        `xml:"xmlns:xsi,attr"`
+	SchemaLocation string        `xml:"xsi:noNamespaceSchemaLocation,attr"`
+	Orders         []interface{} `xml:"Orders>Order"`
+}
+
+type xmlDomesticTransaction struct {
+	XMLName          xml.Name `xml:"DomesticTransaction"`
+	AccountFrom      string   `xml:"accountFrom"`
+	Currency         string   `xml:"currency"`
+	Amount           string   `xml:"amount"`
+	AccountTo        string   `xml:"accountTo"`
+	BankCode         string   `xml:"bankCode"`
+	ConstantSymbol   string   `xml:"ks,omitempty"`
+	VariableSymbol   string   `xml:"vs,omitempty"`
+	SpecificSymbol   string   `xml:"ss,omitempty"`
+	Date             string   `xml:"date"`
+	RecipientMessage string   `xml:"messageForRecipient,omitempty"`
+	Comment          string   `xml:"comment,omitempty"`
+	PaymentReason    string   `xml:"paymentReason,omitempty"`
+	PaymentType      string   `xml:"paymentType,omitempty"`
+}
+
+type xmlT2Transaction struct {
+	XMLName         xml.Name `xml:"T2Transaction"`
+	AccountFrom     string   `xml:"accountFrom"`
+	Currency        string   `xml:"currency"`
+	Amount          string   `xml:"amount"`
+	AccountTo       string   `xml:"accountTo"`
+	ConstantSymbol  string   `xml:"ks,omitempty"`
+	VariableSymbol  string   `xml:"vs,omitempty"`
+	SpecificSymbol  string   `xml:"ss,omitempty"`
+	BIC             string   `xml:"bic,omitempty"`
+	Date            string   `xml:"date"`
+	Comment         string   `xml:"comment,omitempty"`
+	BenefName       string   `xml:"benefName"`
+	BenefStreet     string   `xml:"benefStreet,omitempty"`
+	BenefCity       string   `xml:"benefCity,omitempty"`
+	BenefCountry    string   `xml:"benefCountry,omitempty"`
+	RemittanceInfo1 string   `xml:"remittanceInfo1,omitempty"`
+	RemittanceInfo2 string   `xml:"remittanceInfo2,omitempty"`
+	RemittanceInfo3 string   `xml:"remittanceInfo3,omitempty"`
+	RemittanceInfo4 string   `xml:"remittanceInfo4,omitempty"`
+	PaymentReason   string   `xml:"paymentReason,omitempty"`
+	PaymentType     string   `xml:"paymentType,omitempty"`
+}
+
+type xmlForeignTransaction struct {
+	XMLName          xml.Name `xml:"ForeignTransaction"`
+	AccountFrom      string   `xml:"accountFrom"`
+	Currency         string   `xml:"currency"`
+	Amount           string   `xml:"amount"`
+	AccountTo        string   `xml:"accountTo"`
+	BIC              string   `xml:"bic"`
+	Date             string   `xml:"date"`
+	Comment          string   `xml:"comment,omitempty"`
+	BenefName        string   `xml:"benefName"`
+	BenefStreet      string   `xml:"benefStreet"`
+	BenefCity        string   `xml:"benefCity"`
+	BenefCountry     string   `xml:"benefCountry"`
+	RemittanceInfo1  string   `xml:"remittanceInfo1"`
+	RemittanceInfo2  string   `xml:"remittanceInfo2,omitempty"`
+	RemittanceInfo3  string   `xml:"remittanceInfo3,omitempty"`
+	RemittanceInfo4  string   `xml:"remittanceInfo4,omitempty"`
+	DetailsOfCharges string   `xml:"detailsOfCharges"`
+	PaymentReason    string   `xml:"paymentReason"`
+}
+
+// ImportOptions represents options passed to Import.
+type ImportOptions struct {
+	Orders []Order
+
+	// Language of the messages returned in ImportResult (cs, en or sk).
+	Language string
+}
+
+// ImportStatus represents status of the imported batch or order.
+type ImportStatus string
+
+// Supported ImportStatus values.
+const (
+	ImportStatusOK      ImportStatus = "ok"
+	ImportStatusWarning ImportStatus = "warning"
+	ImportStatusError   ImportStatus = "error"
+)
+
+// ImportResult represents result of the payment orders import.
+type ImportResult struct {
+	ErrorCode     int
+	InstructionID string
+	Status        ImportStatus
+	Sums          []ImportSum
+	Details       []ImportDetail
+}
+
+// Failed returns details of the orders which were rejected by the bank.
+func (r *ImportResult) Failed() []ImportDetail {
+	var failed []ImportDetail
+	for _, d := range r.Details {
+		if d.Status() == ImportStatusError {
+			failed = append(failed, d)
+		}
+	}
+	return failed
+}
+
+// ImportSum represents sum of the imported orders in one currency.
+type ImportSum struct {
+	Currency string
+	Credit   decimal.Decimal
+	Debit    decimal.Decimal
+}
+
+// ImportDetail represents messages related to a single imported order,
+// ID is the position of the order in the imported batch starting from 1.
+type ImportDetail struct {
+	ID       int
+	Messages []ImportMessage
+}
+
+// Status returns the most severe status of the order messages.
+func (d ImportDetail) Status() ImportStatus {
+	status := ImportStatusOK
+	for _, m := range d.Messages {
+		switch m.Status {
+		case ImportStatusError:
+			return ImportStatusError
+		case ImportStatusWarning:
+			status = ImportStatusWarning
+		}
+	}
+	return status
+}
+
+// ImportMessage represents a single message related to imported order.
+type ImportMessage struct {
+	Status    ImportStatus
+	ErrorCode int
+	Message   string
+}
+
+// PaymentsService is a service for importing payment orders.
+type PaymentsService struct {
+	client *Client
+}
+
+// Import uploads payment orders to the bank. Batch rejected by the bank is
+// returned together with *ImportError, even when the response status is 200.
+func (s *PaymentsService) Import(ctx context.Context, opts ImportOptions) (*ImportResult, error) {
+	doc, err := encodeImport(opts.Orders)
+	if err != nil {
+		return nil, err
+	}
+	return s.upload(ctx, XMLImportFormat, opts.Language, bytes.NewReader(doc))
+}
+
+// ImportFile uploads pre-built file with payment orders in given format to the bank,
+// rejections are reported the same way as by Import.
+func (s *PaymentsService) ImportFile(ctx context.Context, format ImportFormat, r io.Reader) (*ImportResult, error) {
+	return s.upload(ctx, format, "", r)
+}
+
+func (s *PaymentsService) upload(ctx context.Context, format ImportFormat, lang string, r io.Reader) (*ImportResult, error) {
+	fields := map[string]string{
+		"type":  string(format),
+		"token": s.client.Token,
+	}
+	if lang != "" {
+		fields["lng"] = lang
+	}
+
+	urlStr := s.client.buildBaseURL("v1/rest/import/")
+	req, err := s.client.newMultipartRequest(ctx, urlStr, fields, "file", format.fileName(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	res, err := parseImportResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.ErrorCode != 0 || res.Status == ImportStatusError {
+		return res, &ImportError{Result: res}
+	}
+	return res, nil
+}
+
+func encodeImport(orders []Order) ([]byte, error) {
+	if len(orders) == 0 {
+		return nil, errors.New("no orders to import")
+	}
+
+	doc := xmlImport{
+		XSI:            importXSINamespace,
+		SchemaLocation: importSchemaLocation,
+	}
+	for i, o := range orders {
+		if err := o.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid order %d: %w", i+1, err)
+		}
+		doc.Orders = append(doc.Orders, o.xmlOrder())
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fmtAmount(d decimal.Decimal) string {
+	return d.StringFixed(2)
+}
+
+func requiredString(name string, v string) error {
+	if v == "" {
+		return fmt.Errorf(`missing required field: "%v"`, name)
+	}
+	return nil
+}
+
+func requiredAmount(name string, v decimal.Decimal) error {
+	if !v.IsPositive() {
+		return fmt.Errorf(`field must be positive: "%v"`, name)
+	}
+	return nil
+}
+
+func requiredDate(name string, v time.Time) error {
+	if v.IsZero() {
+		return fmt.Errorf(`missing required field: "%v"`, name)
+	}
+	return nil
+}
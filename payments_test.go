@@ -0,0 +1,439 @@
+package fio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const importResponse = `
+<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<responseImport xsi:noNamespaceSchemaLocation="http://www.fio.cz/schema/responseImport.xsd" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <result>
+    <errorCode>0</errorCode>
+    <idInstruction>105576</idInstruction>
+    <status>ok</status>
+    <sums>
+      <sum id="CZK">
+        <sumCredit>0</sumCredit>
+        <sumDebit>100.00</sumDebit>
+      </sum>
+    </sums>
+  </result>
+  <ordersDetails>
+    <detail id="1">
+      <messages>
+        <message status="ok" errorCode="0">OK</message>
+      </messages>
+    </detail>
+  </ordersDetails>
+</responseImport>
+`
+
+const domesticImportDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<Import xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="http://www.fio.cz/schema/importIB.xsd">
+  <Orders>
+    <DomesticTransaction>
+      <accountFrom>1234562</accountFrom>
+      <currency>CZK</currency>
+      <amount>100.00</amount>
+      <accountTo>2212-2000000699</accountTo>
+      <bankCode>0300</bankCode>
+      <ks>0558</ks>
+      <vs>1234567890</vs>
+      <date>2013-04-25</date>
+      <messageForRecipient>Hračky pro děti</messageForRecipient>
+      <paymentType>431001</paymentType>
+    </DomesticTransaction>
+  </Orders>
+</Import>`
+
+var testDomesticTransaction = DomesticTransaction{
+	AccountFrom:      "1234562",
+	Currency:         "CZK",
+	Amount:           decimal.NewFromInt(100),
+	AccountTo:        "2212-2000000699",
+	BankCode:         "0300",
+	ConstantSymbol:   "0558",
+	VariableSymbol:   "1234567890",
+	Date:             time.Date(2013, time.April, 25, 0, 0, 0, 0, time.UTC),
+	RecipientMessage: "Hračky pro děti",
+	PaymentType:      PaymentTypeStandard,
+}
+
+func TestEncodeImport(t *testing.T) {
+	doc, err := encodeImport([]Order{testDomesticTransaction})
+
+	require.NoError(t, err)
+	require.Equal(t, domesticImportDocument, string(doc))
+}
+
+func TestImport(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/rest/import/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "xml", r.FormValue("type"))
+		assert.Equal(t, testingToken, r.FormValue("token"))
+		assert.Equal(t, "en", r.FormValue("lng"))
+
+		f, hdr, err := r.FormFile("file")
+		if assert.NoError(t, err) {
+			defer f.Close()
+			data, err := io.ReadAll(f)
+			assert.NoError(t, err)
+			assert.Equal(t, "import.xml", hdr.Filename)
+			assert.Equal(t, domesticImportDocument, string(data))
+		}
+
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, importResponse)
+	})
+
+	opts := ImportOptions{
+		Orders:   []Order{testDomesticTransaction},
+		Language: "en",
+	}
+	resp, err := client.Payments.Import(context.Background(), opts)
+
+	require.NoError(t, err)
+
+	want := &ImportResult{
+		ErrorCode:     0,
+		InstructionID: "105576",
+		Status:        ImportStatusOK,
+		Sums: []ImportSum{
+			{
+				Currency: "CZK",
+				Credit:   decimal.RequireFromString("0"),
+				Debit:    decimal.RequireFromString("100.00"),
+			},
+		},
+		Details: []ImportDetail{
+			{
+				ID: 1,
+				Messages: []ImportMessage{
+					{Status: ImportStatusOK, ErrorCode: 0, Message: "OK"},
+				},
+			},
+		},
+	}
+	require.Equal(t, want, resp)
+	require.Empty(t, resp.Failed())
+}
+
+const importErrorResponse = `
+<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<responseImport xsi:noNamespaceSchemaLocation="http://www.fio.cz/schema/responseImport.xsd" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <result>
+    <errorCode>1</errorCode>
+    <status>error</status>
+    <sums>
+      <sum id="CZK">
+        <sumCredit>0</sumCredit>
+        <sumDebit>200.00</sumDebit>
+      </sum>
+    </sums>
+  </result>
+  <ordersDetails>
+    <detail id="1">
+      <messages>
+        <message status="ok" errorCode="0">OK</message>
+      </messages>
+    </detail>
+    <detail id="2">
+      <messages>
+        <message status="warning" errorCode="1107">Datum splatnosti je v minulosti.</message>
+        <message status="error" errorCode="1002">Chybné číslo účtu příjemce.</message>
+      </messages>
+    </detail>
+  </ordersDetails>
+</responseImport>
+`
+
+func TestImportRejected(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/rest/import/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, importErrorResponse)
+	})
+
+	opts := ImportOptions{
+		Orders: []Order{testDomesticTransaction, testDomesticTransaction},
+	}
+	_, err := client.Payments.Import(context.Background(), opts)
+
+	var errResp *ErrorResponse
+	require.ErrorAs(t, err, &errResp)
+	require.Equal(t, "order 2: Chybné číslo účtu příjemce.", errResp.Message)
+	require.NotNil(t, errResp.Import)
+	require.Equal(t, ImportStatusError, errResp.Import.Status)
+	require.Equal(t, 1, errResp.Import.ErrorCode)
+
+	failed := errResp.Import.Failed()
+	require.Len(t, failed, 1)
+	require.Equal(t, 2, failed[0].ID)
+	require.Equal(t, ImportStatusError, failed[0].Status())
+	require.Equal(t, 1002, failed[0].Messages[1].ErrorCode)
+
+	var importErr *ImportError
+	require.ErrorAs(t, err, &importErr)
+	require.Same(t, errResp.Import, importErr.Result)
+	require.NotErrorIs(t, err, ErrInvalidDate)
+	require.NotErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestImportRejectedWithOK(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/rest/import/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+		fmt.Fprint(w, importErrorResponse)
+	})
+
+	opts := ImportOptions{
+		Orders: []Order{testDomesticTransaction, testDomesticTransaction},
+	}
+	res, err := client.Payments.Import(context.Background(), opts)
+
+	var importErr *ImportError
+	require.ErrorAs(t, err, &importErr)
+	require.NotNil(t, res)
+	require.Same(t, res, importErr.Result)
+	require.Equal(t, ImportStatusError, res.Status)
+	require.Equal(t, 1, res.ErrorCode)
+	require.Equal(t, "import rejected: order 2: Chybné číslo účtu příjemce.", importErr.Error())
+}
+
+func TestImportRejectedInternalServerError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/rest/import/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, importErrorResponse)
+	})
+
+	opts := ImportOptions{
+		Orders: []Order{testDomesticTransaction, testDomesticTransaction},
+	}
+	_, err := client.Payments.Import(context.Background(), opts)
+
+	var importErr *ImportError
+	require.ErrorAs(t, err, &importErr)
+	require.Equal(t, ImportStatusError, importErr.Result.Status)
+	require.Equal(t, "import rejected: order 2: Chybné číslo účtu příjemce.", importErr.Error())
+	require.NotErrorIs(t, err, ErrInvalidToken)
+
+	var validationErr *ValidationError
+	require.False(t, errors.As(err, &validationErr))
+}
+
+const euroImportDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<Import xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:noNamespaceSchemaLocation="http://www.fio.cz/schema/importIB.xsd">
+  <Orders>
+    <T2Transaction>
+      <accountFrom>1234562</accountFrom>
+      <currency>EUR</currency>
+      <amount>50.50</amount>
+      <accountTo>AT611904300234573201</accountTo>
+      <bic>ABAGATWWXXX</bic>
+      <date>2013-04-25</date>
+      <benefName>Hans Gruber</benefName>
+      <benefCountry>AT</benefCountry>
+      <remittanceInfo1>Invoice 2013/42</remittanceInfo1>
+      <paymentType>431008</paymentType>
+    </T2Transaction>
+    <ForeignTransaction>
+      <accountFrom>1234562</accountFrom>
+      <currency>USD</currency>
+      <amount>1200.00</amount>
+      <accountTo>PK36SCBL0000001123456702</accountTo>
+      <bic>ALFHPKKAXXX</bic>
+      <date>2013-04-25</date>
+      <benefName>Amir Khan</benefName>
+      <benefStreet>Nishtar Rd 13</benefStreet>
+      <benefCity>Karachi</benefCity>
+      <benefCountry>PK</benefCountry>
+      <remittanceInfo1>Invoice 2013/43</remittanceInfo1>
+      <detailsOfCharges>470502</detailsOfCharges>
+      <paymentReason>348</paymentReason>
+    </ForeignTransaction>
+  </Orders>
+</Import>`
+
+var (
+	testT2Transaction = T2Transaction{
+		AccountFrom:     "1234562",
+		Currency:        "EUR",
+		Amount:          decimal.RequireFromString("50.5"),
+		AccountTo:       "AT611904300234573201",
+		BIC:             "ABAGATWWXXX",
+		Date:            time.Date(2013, time.April, 25, 0, 0, 0, 0, time.UTC),
+		BenefName:       "Hans Gruber",
+		BenefCountry:    "AT",
+		RemittanceInfo1: "Invoice 2013/42",
+		PaymentType:     PaymentTypeEuroStandard,
+	}
+
+	testForeignTransaction = ForeignTransaction{
+		AccountFrom:      "1234562",
+		Currency:         "USD",
+		Amount:           decimal.NewFromInt(1200),
+		AccountTo:        "PK36SCBL0000001123456702",
+		BIC:              "ALFHPKKAXXX",
+		Date:             time.Date(2013, time.April, 25, 0, 0, 0, 0, time.UTC),
+		BenefName:        "Amir Khan",
+		BenefStreet:      "Nishtar Rd 13",
+		BenefCity:        "Karachi",
+		BenefCountry:     "PK",
+		RemittanceInfo1:  "Invoice 2013/43",
+		DetailsOfCharges: ChargesBen,
+		PaymentReason:    "348",
+	}
+)
+
+func TestEncodeImportEuroAndForeign(t *testing.T) {
+	doc, err := encodeImport([]Order{testT2Transaction, testForeignTransaction})
+
+	require.NoError(t, err)
+	require.Equal(t, euroImportDocument, string(doc))
+}
+
+var validateOrderCases = []struct {
+	name    string
+	order   Order
+	missing []string
+}{
+	{
+		name:  "domestic valid",
+		order: testDomesticTransaction,
+	},
+	{
+		name:    "domestic empty",
+		order:   DomesticTransaction{},
+		missing: []string{"accountFrom", "currency", "amount", "accountTo", "bankCode", "date"},
+	},
+	{
+		name:  "t2 valid",
+		order: testT2Transaction,
+	},
+	{
+		name:    "t2 empty",
+		order:   T2Transaction{},
+		missing: []string{"accountFrom", "currency", "amount", "accountTo", "date", "benefName"},
+	},
+	{
+		name:  "foreign valid",
+		order: testForeignTransaction,
+	},
+	{
+		name: "foreign missing charges",
+		order: ForeignTransaction{
+			AccountFrom:     "1234562",
+			Currency:        "USD",
+			Amount:          decimal.NewFromInt(-1),
+			AccountTo:       "PK36SCBL0000001123456702",
+			BIC:             "ALFHPKKAXXX",
+			Date:            time.Now(),
+			BenefName:       "Amir Khan",
+			BenefStreet:     "Nishtar Rd 13",
+			BenefCity:       "Karachi",
+			BenefCountry:    "PK",
+			RemittanceInfo1: "Invoice",
+			PaymentReason:   "348",
+		},
+		missing: []string{"amount", "detailsOfCharges"},
+	},
+}
+
+func TestValidateOrder(t *testing.T) {
+	for _, c := range validateOrderCases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.order.Validate()
+			if len(c.missing) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, name := range c.missing {
+				require.Contains(t, err.Error(), fmt.Sprintf(`"%v"`, name))
+			}
+		})
+	}
+}
+
+func TestImportInvalidOrder(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/v1/rest/import/", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not be sent")
+	})
+
+	opts := ImportOptions{
+		Orders: []Order{testDomesticTransaction, T2Transaction{}},
+	}
+	_, err := client.Payments.Import(context.Background(), opts)
+
+	require.ErrorContains(t, err, "invalid order 2")
+}
+
+const importFileContent = "payment orders"
+
+var importFileCases = []struct {
+	format   ImportFormat
+	fileName string
+}{
+	{format: XMLImportFormat, fileName: "import.xml"},
+	{format: ABOImportFormat, fileName: "import.abo"},
+	{format: Pain001ImportFormat, fileName: "import.xml"},
+}
+
+func TestImportFile(t *testing.T) {
+	for _, c := range importFileCases {
+		t.Run(string(c.format), func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			mux.HandleFunc("/v1/rest/import/", func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, string(c.format), r.FormValue("type"))
+				assert.Equal(t, testingToken, r.FormValue("token"))
+
+				f, hdr, err := r.FormFile("file")
+				if assert.NoError(t, err) {
+					defer f.Close()
+					data, err := io.ReadAll(f)
+					assert.NoError(t, err)
+					assert.Equal(t, c.fileName, hdr.Filename)
+					assert.Equal(t, importFileContent, string(data))
+				}
+
+				w.Header().Set("Content-Type", "text/xml")
+				fmt.Fprint(w, importResponse)
+			})
+
+			resp, err := client.Payments.ImportFile(context.Background(), c.format, strings.NewReader(importFileContent))
+
+			require.NoError(t, err)
+			require.Equal(t, "105576", resp.InstructionID)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package fio
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimitInterval is the minimal interval between two requests
+// made with the same token which is allowed by the fio API.
+const DefaultRateLimitInterval = 30 * time.Second
+
+// NewRateLimiter returns new rate limiter allowing one request per interval for each token.
+func NewRateLimiter(interval time.Duration) *RateLimiter {
+	return &RateLimiter{
+		interval: interval,
+		next:     make(map[string]time.Time),
+	}
+}
+
+// RateLimiter limits the rate of requests made with the same token.
+// It is safe for concurrent use and can be shared by multiple clients.
+type RateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// Interval returns minimal interval between two requests made with the same token.
+func (l *RateLimiter) Interval() time.Duration {
+	return l.interval
+}
+
+// Wait blocks until the next request with token is allowed or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, token string) error {
+	slot, reserved := l.reserve(token)
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release(token, slot, reserved)
+		return ctx.Err()
+	}
+}
+
+func (l *RateLimiter) reserve(token string) (time.Time, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	slot := l.next[token]
+	if slot.Before(now) {
+		slot = now
+	}
+	reserved := slot.Add(l.interval)
+	l.next[token] = reserved
+	return slot, reserved
+}
+
+// release gives the slot back unless another request has been scheduled after it.
+func (l *RateLimiter) release(token string, slot time.Time, reserved time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.next[token].Equal(reserved) {
+		l.next[token] = slot
+	}
+}
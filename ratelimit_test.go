@@ -0,0 +1,71 @@
+package fio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	interval := 50 * time.Millisecond
+	limiter := NewRateLimiter(interval)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, limiter.Wait(context.Background(), "token"))
+		}()
+	}
+	wg.Wait()
+
+	require.GreaterOrEqual(t, time.Since(start), 2*interval)
+}
+
+func TestRateLimiterPerToken(t *testing.T) {
+	limiter := NewRateLimiter(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, limiter.Wait(ctx, "first"))
+	require.NoError(t, limiter.Wait(ctx, "second"))
+}
+
+func TestRateLimiterContextCancel(t *testing.T) {
+	limiter := NewRateLimiter(time.Hour)
+	require.NoError(t, limiter.Wait(context.Background(), "token"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := limiter.Wait(ctx, "token")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClientRateLimit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	interval := 50 * time.Millisecond
+	client.Limiter = NewRateLimiter(interval)
+
+	urlStr := fmt.Sprintf("/v1/rest/set-last-id/%v/1/", testingToken)
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {})
+
+	start := time.Now()
+	for range 3 {
+		err := client.Transactions.SetLastDownloadID(context.Background(), SetLastDownloadIDOptions{ID: 1})
+		require.NoError(t, err)
+	}
+
+	require.GreaterOrEqual(t, time.Since(start), 2*interval)
+}
@@ -0,0 +1,126 @@
+package fio
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryPolicy returns retry policy suitable for most of the use cases.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     []time.Duration{time.Second, 5 * time.Second, 15 * time.Second},
+		Jitter:      0.2,
+	}
+}
+
+// RetryPolicy configures retrying of failed requests. Only idempotent GET and HEAD
+// requests are retried, so payment imports are never sent twice. Requests rejected
+// with 409 are retried no sooner than after the rate limit interval, which is the
+// client Limiter interval or DefaultRateLimitInterval when there is no Limiter.
+type RetryPolicy struct {
+	// MaxAttempts is the maximal number of attempts including the first one.
+	MaxAttempts int
+
+	// Backoff is the schedule of delays between attempts, the last
+	// delay is reused when the schedule is exhausted.
+	Backoff []time.Duration
+
+	// Jitter is the fraction of the delay which is randomized, from 0 to 1.
+	Jitter float64
+
+	// Retryable reports whether failed idempotent request should be
+	// retried, DefaultRetryable is used when nil.
+	Retryable func(req *http.Request, err error) bool
+}
+
+// DefaultRetryable retries network errors, rate limited and temporarily
+// unavailable responses.
+func DefaultRetryable(req *http.Request, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+
+	var errResp *ErrorResponse
+	if errors.As(err, &errResp) {
+		switch errResp.Response.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (p *RetryPolicy) shouldRetry(req *http.Request, err error, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+	return retryable(req, err)
+}
+
+// delay returns delay before the next attempt, rate limited requests
+// wait at least for the rateLimit interval.
+func (p *RetryPolicy) delay(attempt int, err error, rateLimit time.Duration) time.Duration {
+	d := p.backoff(attempt)
+	if errors.Is(err, ErrRateLimited) {
+		d = max(d, rateLimit)
+	}
+	return d
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	if len(p.Backoff) == 0 {
+		return 0
+	}
+
+	d := p.Backoff[min(attempt, len(p.Backoff))-1]
+	if p.Jitter > 0 {
+		j := time.Duration(p.Jitter * float64(d))
+		d = d - j + rand.N(2*j+1)
+	}
+	return d
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
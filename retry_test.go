@@ -0,0 +1,209 @@
+package fio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryRateLimited(t *testing.T) {
+	setup()
+	defer teardown()
+
+	interval := 20 * time.Millisecond
+	client.Limiter = NewRateLimiter(interval)
+	client.Retry = &RetryPolicy{MaxAttempts: 3}
+
+	var calls atomic.Int32
+	urlStr := fmt.Sprintf("/v1/rest/set-last-id/%v/1/", testingToken)
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusConflict)
+		}
+	})
+
+	start := time.Now()
+	err := client.Transactions.SetLastDownloadID(context.Background(), SetLastDownloadIDOptions{ID: 1})
+
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+	require.GreaterOrEqual(t, time.Since(start), 2*interval)
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+	client.Retry = &RetryPolicy{
+		MaxAttempts: 2,
+		Backoff:     []time.Duration{time.Millisecond},
+		Jitter:      0.5,
+	}
+
+	var calls atomic.Int32
+	urlStr := fmt.Sprintf("/v1/rest/set-last-id/%v/1/", testingToken)
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	err := client.Transactions.SetLastDownloadID(context.Background(), SetLastDownloadIDOptions{ID: 1})
+
+	var errResp *ErrorResponse
+	require.ErrorAs(t, err, &errResp)
+	require.Equal(t, http.StatusServiceUnavailable, errResp.Response.StatusCode)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryNotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+	client.Retry = &RetryPolicy{MaxAttempts: 3}
+
+	var calls atomic.Int32
+	urlStr := fmt.Sprintf("/v1/rest/set-last-id/%v/1/", testingToken)
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	err := client.Transactions.SetLastDownloadID(context.Background(), SetLastDownloadIDOptions{ID: 1})
+
+	require.Error(t, err)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryImportNotRetried(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+	client.Retry = &RetryPolicy{MaxAttempts: 3}
+
+	var calls atomic.Int32
+	mux.HandleFunc("/v1/rest/import/", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	_, err := client.Payments.Import(context.Background(), ImportOptions{Orders: []Order{testDomesticTransaction}})
+
+	require.Error(t, err)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryCustomRetryableImportNotRetried(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+	client.Retry = &RetryPolicy{
+		MaxAttempts: 3,
+		Retryable: func(req *http.Request, err error) bool {
+			return true
+		},
+	}
+
+	var calls atomic.Int32
+	mux.HandleFunc("/v1/rest/import/", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := client.Payments.Import(context.Background(), ImportOptions{Orders: []Order{testDomesticTransaction}})
+
+	require.Error(t, err)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRewindRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.com", strings.NewReader("body"))
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	require.Equal(t, "body", string(data))
+
+	r, err := rewindRequest(req)
+	require.NoError(t, err)
+
+	data, err = io.ReadAll(r.Body)
+	require.NoError(t, err)
+	require.Equal(t, "body", string(data))
+}
+
+func TestRetryRateLimitedWithoutLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+	client.Retry = &RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     []time.Duration{time.Millisecond},
+	}
+
+	var calls atomic.Int32
+	urlStr := fmt.Sprintf("/v1/rest/set-last-id/%v/1/", testingToken)
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := client.Transactions.SetLastDownloadID(ctx, SetLastDownloadIDOptions{ID: 1})
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	rateLimited := &ErrorResponse{Err: ErrRateLimited}
+	unavailable := errors.New("unavailable")
+
+	require.GreaterOrEqual(t, policy.delay(1, rateLimited, DefaultRateLimitInterval), DefaultRateLimitInterval)
+	require.GreaterOrEqual(t, policy.delay(3, rateLimited, time.Minute), time.Minute)
+	require.Less(t, policy.delay(1, unavailable, DefaultRateLimitInterval), 2*time.Second)
+
+	client := NewClient(testingToken, nil)
+	client.Limiter = nil
+	require.Equal(t, DefaultRateLimitInterval, client.rateLimitInterval())
+	client.Limiter = NewRateLimiter(time.Second)
+	require.Equal(t, time.Second, client.rateLimitInterval())
+}
+
+func TestRetryContextCancel(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+	client.Retry = &RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     []time.Duration{time.Hour},
+	}
+
+	urlStr := fmt.Sprintf("/v1/rest/set-last-id/%v/1/", testingToken)
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := client.Transactions.SetLastDownloadID(ctx, SetLastDownloadIDOptions{ID: 1})
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,150 @@
+package fio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrNoCursor is returned by Sync when the CursorStore holds no cursor yet,
+// use Syncer.Start to record the starting point of the sync.
+var ErrNoCursor = errors.New("no sync cursor, call Start first")
+
+// CursorStore persists id of the last successfully processed transaction.
+type CursorStore interface {
+	// Load returns the stored cursor, zero is returned when there is none.
+	Load(ctx context.Context) (int64, error)
+
+	// Save stores the cursor.
+	Save(ctx context.Context, id int64) error
+}
+
+// FileCursorStore is a CursorStore keeping the cursor in a file.
+type FileCursorStore struct {
+	Path string
+}
+
+// Load returns the cursor stored in the file, zero is returned when the file does not exist.
+func (s *FileCursorStore) Load(ctx context.Context) (int64, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return parseOptionalInteger(strings.TrimSpace(string(data)))
+}
+
+// Save atomically replaces the file with the cursor.
+func (s *FileCursorStore) Save(ctx context.Context, id int64) error {
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(strconv.FormatInt(id, 10)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.Path)
+}
+
+// Batch represents transactions downloaded by a single Sync.
+type Batch struct {
+	Info         StatementInfo
+	Transactions []Transaction
+
+	// Key identifies the batch, a batch redelivered after failure starts
+	// with the same transaction. Transaction.ID can be used as an
+	// idempotency key of the single transaction.
+	Key string
+}
+
+// SyncHandler processes downloaded batch of transactions.
+type SyncHandler func(ctx context.Context, batch Batch) error
+
+// NewSyncer returns new syncer using client to download transactions and store to keep the cursor.
+func NewSyncer(client *Client, store CursorStore) *Syncer {
+	return &Syncer{
+		client: client,
+		store:  store,
+	}
+}
+
+// Syncer downloads transactions since the last download and advances
+// the cursor only after they are successfully processed, which gives
+// at-least-once delivery of every transaction.
+type Syncer struct {
+	client *Client
+	store  CursorStore
+}
+
+// Start records id of the last transaction which does not need to be synced as the
+// starting point, it must be called before the first Sync. Both local and server
+// side cursors are set, so Start can be repeated until the first Sync succeeds.
+func (s *Syncer) Start(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid sync cursor: %d", id)
+	}
+	if err := s.store.Save(ctx, id); err != nil {
+		return err
+	}
+	return s.setServerCursor(ctx, id)
+}
+
+// Sync downloads new transactions and passes them to handler. The local cursor is
+// advanced only when handler succeeds, the server side cursor is moved back to it
+// when handler fails and at the start of every Sync. Sync makes two requests, three
+// when handler fails, which are rate limited by the client Limiter. ErrNoCursor is
+// returned when Start has not been called.
+func (s *Syncer) Sync(ctx context.Context, handler SyncHandler) error {
+	cursor, err := s.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if cursor == 0 {
+		return ErrNoCursor
+	}
+
+	// rewind the server side cursor in case the previous sync has not finished
+	if err := s.setServerCursor(ctx, cursor); err != nil {
+		return err
+	}
+
+	resp, err := s.client.Transactions.SinceLastDownload(ctx)
+	if err != nil {
+		return err
+	}
+	if len(resp.Transactions) == 0 {
+		return nil
+	}
+
+	batch := Batch{
+		Info:         resp.Info,
+		Transactions: resp.Transactions,
+		Key:          fmt.Sprintf("%d-%d", resp.Info.IDFrom, resp.Info.IDTo),
+	}
+	if err := handler(ctx, batch); err != nil {
+		return errors.Join(err, s.setServerCursor(ctx, cursor))
+	}
+
+	// the download has already moved the server side cursor to IDTo
+	return s.store.Save(ctx, resp.Info.IDTo)
+}
+
+func (s *Syncer) setServerCursor(ctx context.Context, id int64) error {
+	return s.client.Transactions.SetLastDownloadID(ctx, SetLastDownloadIDOptions{ID: int(id)})
+}
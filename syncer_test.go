@@ -0,0 +1,144 @@
+package fio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// setupLastDownload registers handlers emulating server side last download cursor.
+func setupLastDownload(cursor *int64, ids []int64) {
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/set-last-id/%v/", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		idStr := strings.Trim(strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/v1/rest/set-last-id/%v/", testingToken)), "/")
+		id, _ := strconv.ParseInt(idStr, 10, 64)
+		*cursor = id
+	})
+	mux.HandleFunc(fmt.Sprintf("/ib_api/rest/last/%v/transactions.xml", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		var pending []int64
+		for _, id := range ids {
+			if id > *cursor {
+				pending = append(pending, id)
+			}
+		}
+		body := chunkTransactionsResponse("0.00", "0.00", pending...)
+		body = strings.Replace(body, "<Info>", fmt.Sprintf("<Info><idLastDownload>%v</idLastDownload>", *cursor), 1)
+		if len(pending) > 0 {
+			*cursor = pending[len(pending)-1]
+		}
+		fmt.Fprint(w, body)
+	})
+}
+
+func TestSyncer(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+
+	cursor := int64(10)
+	setupLastDownload(&cursor, []int64{10, 11, 12})
+
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor")}
+	syncer := NewSyncer(client, store)
+	require.NoError(t, syncer.Start(context.Background(), 10))
+
+	var batches []Batch
+	handler := func(ctx context.Context, batch Batch) error {
+		batches = append(batches, batch)
+		return nil
+	}
+
+	require.NoError(t, syncer.Sync(context.Background(), handler))
+	require.Len(t, batches, 1)
+	require.Equal(t, "11-12", batches[0].Key)
+	require.Len(t, batches[0].Transactions, 2)
+
+	stored, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(12), stored)
+	require.Equal(t, int64(12), cursor)
+
+	require.NoError(t, syncer.Sync(context.Background(), handler))
+	require.Len(t, batches, 1)
+}
+
+func TestSyncerWithoutStart(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+
+	cursor := int64(10)
+	setupLastDownload(&cursor, []int64{10, 11, 12})
+
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor")}
+	syncer := NewSyncer(client, store)
+
+	err := syncer.Sync(context.Background(), func(ctx context.Context, batch Batch) error {
+		return nil
+	})
+	require.ErrorIs(t, err, ErrNoCursor)
+	require.Equal(t, int64(10), cursor)
+
+	require.Error(t, syncer.Start(context.Background(), 0))
+}
+
+func TestSyncerHandlerErrorOnFirstRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+
+	// the server side cursor is already ahead, Start rewinds it
+	cursor := int64(12)
+	setupLastDownload(&cursor, []int64{10, 11, 12})
+
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor")}
+	syncer := NewSyncer(client, store)
+	require.NoError(t, syncer.Start(context.Background(), 10))
+	require.Equal(t, int64(10), cursor)
+
+	handlerErr := errors.New("handler failed")
+	err := syncer.Sync(context.Background(), func(ctx context.Context, batch Batch) error {
+		return handlerErr
+	})
+	require.ErrorIs(t, err, handlerErr)
+
+	stored, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(10), stored)
+	require.Equal(t, int64(10), cursor)
+
+	// simulate crash after the download, the batch must be delivered again
+	cursor = 12
+
+	var batches []Batch
+	err = syncer.Sync(context.Background(), func(ctx context.Context, batch Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	require.Equal(t, "11-12", batches[0].Key)
+}
+
+func TestFileCursorStore(t *testing.T) {
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor")}
+
+	id, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, id)
+
+	require.NoError(t, store.Save(context.Background(), 13926601410))
+
+	id, err = store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(13926601410), id)
+}
@@ -2,8 +2,9 @@ package fio
 
 import (
 	"context"
-	"fmt"
+	"encoding/xml"
 	"io"
+	"iter"
 	"strconv"
 	"time"
 
@@ -50,11 +51,16 @@ type Transaction struct {
 	UserIdentification string
 	RecipientMessage   string
 	Type               string
+	Author             string
 	Specification      string
 	Comment            string
 	BIC                string
 	OrderID            string
 	PayerReference     string
+
+	// Extra holds columns unknown to the parser keyed by their ID,
+	// it is populated only in lenient parsing mode.
+	Extra map[string]Column
 }
 
 // ByPeriodOptions represents options passed to ByPeriod.
@@ -70,7 +76,7 @@ type TransactionsService struct {
 
 // ByPeriod returns transactions in date period.
 func (s *TransactionsService) ByPeriod(ctx context.Context, opts ByPeriodOptions) (*TransactionsResponse, error) {
-	urlStr := s.client.buildURL("v1/rest/periods", fmtDate(opts.DateFrom), fmtDate(opts.DateTo), "transactions.xml")
+	urlStr := s.client.buildURL("v1/rest/periods", fmtDate(opts.DateFrom), fmtDate(opts.DateTo), s.client.transactionsFile())
 	req, err := s.client.newGetRequest(ctx, urlStr)
 	if err != nil {
 		return nil, err
@@ -82,7 +88,133 @@ func (s *TransactionsService) ByPeriod(ctx context.Context, opts ByPeriodOptions
 	}
 
 	defer resp.Body.Close()
-	return parseTransactionsResponse(resp.Body)
+	return s.client.parseTransactions(resp.Body)
+}
+
+// ByPeriodChunkedOptions represents options passed to ByPeriodChunked.
+type ByPeriodChunkedOptions struct {
+	DateFrom time.Time
+	DateTo   time.Time
+
+	// Days is the number of days fetched by a single request, defaults to 30.
+	Days int
+}
+
+// ByPeriodChunked returns transactions in date period fetched sequentially
+// in windows of opts.Days days. The requests are rate limited by the client
+// Limiter, transactions present in multiple windows are returned only once.
+func (s *TransactionsService) ByPeriodChunked(ctx context.Context, opts ByPeriodChunkedOptions) (*TransactionsResponse, error) {
+	days := opts.Days
+	if days <= 0 {
+		days = 30
+	}
+
+	var resps []*TransactionsResponse
+	for from := opts.DateFrom; !dateAfter(from, opts.DateTo); from = from.AddDate(0, 0, days) {
+		to := from.AddDate(0, 0, days-1)
+		if dateAfter(to, opts.DateTo) {
+			to = opts.DateTo
+		}
+
+		resp, err := s.ByPeriod(ctx, ByPeriodOptions{DateFrom: from, DateTo: to})
+		if err != nil {
+			return nil, err
+		}
+		resps = append(resps, resp)
+	}
+	return mergeTransactionsResponses(resps), nil
+}
+
+// mergeTransactionsResponses merges consecutive responses, the opening balance
+// is taken from the first and the closing balance from the last response.
+func mergeTransactionsResponses(resps []*TransactionsResponse) *TransactionsResponse {
+	merged := new(TransactionsResponse)
+	if len(resps) == 0 {
+		return merged
+	}
+
+	first, last := resps[0], resps[len(resps)-1]
+	merged.Info = first.Info
+	merged.Info.ClosingBalance = last.Info.ClosingBalance
+	merged.Info.DateEnd = last.Info.DateEnd
+	merged.Info.IDTo = 0
+
+	seen := make(map[int64]bool)
+	for _, resp := range resps {
+		if merged.Info.IDFrom == 0 {
+			merged.Info.IDFrom = resp.Info.IDFrom
+		}
+		if resp.Info.IDTo != 0 {
+			merged.Info.IDTo = resp.Info.IDTo
+		}
+
+		for _, tx := range resp.Transactions {
+			if seen[tx.ID] {
+				continue
+			}
+			seen[tx.ID] = true
+			merged.Transactions = append(merged.Transactions, tx)
+		}
+	}
+	return merged
+}
+
+// TransactionStream represents statement with transactions read one by one.
+// It must be closed after use.
+type TransactionStream struct {
+	Info StatementInfo
+
+	body io.ReadCloser
+	dec  *xml.Decoder
+	opts ParseOptions
+}
+
+// All returns iterator over the statement transactions, iteration stops
+// after the first error. The stream can be iterated only once.
+func (s *TransactionStream) All() iter.Seq2[Transaction, error] {
+	return func(yield func(Transaction, error) bool) {
+		for {
+			tx, err := s.next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(Transaction{}, err)
+				return
+			}
+			if !yield(*tx, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Close closes the underlying response body.
+func (s *TransactionStream) Close() error {
+	return s.body.Close()
+}
+
+// StreamByPeriod returns transactions in date period as a stream, it keeps
+// the memory usage constant regardless of the number of transactions.
+// The transactions are always downloaded in XMLFormat.
+func (s *TransactionsService) StreamByPeriod(ctx context.Context, opts ByPeriodOptions) (*TransactionStream, error) {
+	urlStr := s.client.buildURL("v1/rest/periods", fmtDate(opts.DateFrom), fmtDate(opts.DateTo), "transactions.xml")
+	req, err := s.client.newGetRequest(ctx, urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := newTransactionStream(resp.Body, s.client.ParseOptions)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return stream, nil
 }
 
 // ExportOptions represents options passed to Export.
@@ -92,10 +224,14 @@ type ExportOptions struct {
 	Format   ExportFormat
 }
 
-// Export writes transactions in date period to provided writer.
+// Export writes transactions in date period to provided writer, formats available
+// only for statements are rejected with ErrUnsupportedFormat.
 func (s *TransactionsService) Export(ctx context.Context, opts ExportOptions, w io.Writer) error {
-	exportFmt := fmt.Sprintf("transactions.%v", opts.Format)
-	urlStr := s.client.buildURL("v1/rest/periods", fmtDate(opts.DateFrom), fmtDate(opts.DateTo), exportFmt)
+	if err := opts.Format.validatePeriod(); err != nil {
+		return err
+	}
+
+	urlStr := s.client.buildURL("v1/rest/periods", fmtDate(opts.DateFrom), fmtDate(opts.DateTo), opts.Format.fileName())
 	req, err := s.client.newGetRequest(ctx, urlStr)
 	if err != nil {
 		return err
@@ -119,7 +255,30 @@ type GetStatementOptions struct {
 
 // GetStatement returns statement by its year/id.
 func (s *TransactionsService) GetStatement(ctx context.Context, opts GetStatementOptions) (*TransactionsResponse, error) {
-	urlStr := s.client.buildURL("v1/rest/by-id", strconv.Itoa(opts.Year), strconv.Itoa(opts.ID), "transactions.xml")
+	urlStr := s.client.buildURL("v1/rest/by-id", strconv.Itoa(opts.Year), strconv.Itoa(opts.ID), s.client.transactionsFile())
+	req, err := s.client.newGetRequest(ctx, urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	return s.client.parseTransactions(resp.Body)
+}
+
+// LastStatementResponse represents year and id of the last issued statement.
+type LastStatementResponse struct {
+	Year int
+	ID   int
+}
+
+// LastStatement returns year and id of the last issued statement.
+func (s *TransactionsService) LastStatement(ctx context.Context) (*LastStatementResponse, error) {
+	urlStr := s.client.buildURL("v1/rest/lastStatement", "statement")
 	req, err := s.client.newGetRequest(ctx, urlStr)
 	if err != nil {
 		return nil, err
@@ -131,7 +290,24 @@ func (s *TransactionsService) GetStatement(ctx context.Context, opts GetStatemen
 	}
 
 	defer resp.Body.Close()
-	return parseTransactionsResponse(resp.Body)
+	return parseLastStatementResponse(resp.Body)
+}
+
+// GetLastStatement returns the last issued statement. It makes two requests with
+// the same token, so with the client Limiter it waits for the rate limit interval
+// between them, which is DefaultRateLimitInterval for clients made by NewClient.
+// The ctx deadline must allow for the wait.
+func (s *TransactionsService) GetLastStatement(ctx context.Context) (*TransactionsResponse, error) {
+	last, err := s.LastStatement(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := GetStatementOptions{
+		Year: last.Year,
+		ID:   last.ID,
+	}
+	return s.GetStatement(ctx, opts)
 }
 
 type ExportStatementOptions struct {
@@ -142,8 +318,11 @@ type ExportStatementOptions struct {
 
 // ExportStatement writes statement by its year/id to provided writer.
 func (s *TransactionsService) ExportStatement(ctx context.Context, opts ExportStatementOptions, w io.Writer) error {
-	exportFmt := fmt.Sprintf("transactions.%v", opts.Format)
-	urlStr := s.client.buildURL("v1/rest/by-id", strconv.Itoa(opts.Year), strconv.Itoa(opts.ID), exportFmt)
+	if err := opts.Format.Validate(); err != nil {
+		return err
+	}
+
+	urlStr := s.client.buildURL("v1/rest/by-id", strconv.Itoa(opts.Year), strconv.Itoa(opts.ID), opts.Format.fileName())
 	req, err := s.client.newGetRequest(ctx, urlStr)
 	if err != nil {
 		return err
@@ -161,7 +340,7 @@ func (s *TransactionsService) ExportStatement(ctx context.Context, opts ExportSt
 
 // SinceLastDownload returns transactions since last download.
 func (s *TransactionsService) SinceLastDownload(ctx context.Context) (*TransactionsResponse, error) {
-	urlStr := s.client.buildURL("ib_api/rest/last", "transactions.xml")
+	urlStr := s.client.buildURL("ib_api/rest/last", s.client.transactionsFile())
 	req, err := s.client.newGetRequest(ctx, urlStr)
 	if err != nil {
 		return nil, err
@@ -173,7 +352,7 @@ func (s *TransactionsService) SinceLastDownload(ctx context.Context) (*Transacti
 	}
 
 	defer resp.Body.Close()
-	return parseTransactionsResponse(resp.Body)
+	return s.client.parseTransactions(resp.Body)
 }
 
 // SetLastDownloadIDOptions represents options passed to SetLastDownloadID.
@@ -219,3 +398,7 @@ func (s *TransactionsService) SetLastDownloadDate(ctx context.Context, opts SetL
 func fmtDate(t time.Time) string {
 	return t.Format(dateFormat)
 }
+
+func dateAfter(a time.Time, b time.Time) bool {
+	return fmtDate(a) > fmtDate(b)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -45,6 +46,7 @@ const transactionsResponse = `
       <column_7 name="Uživatelská identifikace" id="7">john doe</column_7>
       <column_16 name="Zpráva pro příjemce" id="16">/DO2017-04-10/SPPrevod zo zuno, john doe</column_16>
       <column_8 name="Typ" id="8">Bezhotovostní příjem</column_8>
+      <column_9 name="Provedl" id="9">john doe</column_9>
       <column_25 name="Komentář" id="25">john doe</column_25>
       <column_26 name="BIC" id="26">RIDBSKBXXXX</column_26>
       <column_17 name="ID pokynu" id="17">15689512949</column_17>
@@ -115,6 +117,7 @@ func TestByPeriod(t *testing.T) {
 				Specification:      "45.97 EUR",
 				UserIdentification: "john doe",
 				Type:               "Bezhotovostní příjem",
+				Author:             "john doe",
 				PayerReference:     "2000000003",
 			},
 		},
@@ -149,6 +152,50 @@ func TestExport(t *testing.T) {
 	require.Equal(t, len(transactionsResponse), buf.Len())
 }
 
+func TestExportUnsupportedFormat(t *testing.T) {
+	setup()
+	defer teardown()
+
+	for _, format := range []ExportFormat{"", "docx", PDFFormat, CAMT053Format} {
+		opts := ExportOptions{
+			DateFrom: time.Now(),
+			DateTo:   time.Now(),
+			Format:   format,
+		}
+		err := client.Transactions.Export(context.Background(), opts, new(bytes.Buffer))
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+	}
+}
+
+func TestExportStatement(t *testing.T) {
+	setup()
+	defer teardown()
+
+	year := 2017
+	id := 1
+	urlStr := fmt.Sprintf("/v1/rest/by-id/%v/%v/%v/transactions.pdf", testingToken, year, id)
+
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, "%PDF-1.4")
+	})
+
+	buf := new(bytes.Buffer)
+	opts := ExportStatementOptions{
+		Year:   year,
+		ID:     id,
+		Format: PDFFormat,
+	}
+	err := client.Transactions.ExportStatement(context.Background(), opts, buf)
+
+	require.NoError(t, err)
+	require.Equal(t, "%PDF-1.4", buf.String())
+
+	opts.Format = "docx"
+	err = client.Transactions.ExportStatement(context.Background(), opts, buf)
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
 func TestGetStatement(t *testing.T) {
 	setup()
 	defer teardown()
@@ -210,6 +257,7 @@ func TestGetStatement(t *testing.T) {
 				Specification:      "45.97 EUR",
 				UserIdentification: "john doe",
 				Type:               "Bezhotovostní příjem",
+				Author:             "john doe",
 				PayerReference:     "2000000003",
 			},
 		},
@@ -261,6 +309,287 @@ func assertEqualTransaction(t *testing.T, want Transaction, got Transaction) {
 	require.Equal(t, want.Comment, got.Comment)
 	require.Equal(t, want.UserIdentification, got.UserIdentification)
 	require.Equal(t, want.Type, got.Type)
+	require.Equal(t, want.Author, got.Author)
 	require.Equal(t, want.PayerReference, got.PayerReference)
+	require.Equal(t, want.Extra, got.Extra)
 	require.True(t, want.Date.Equal(got.Date))
 }
+
+func TestStreamByPeriod(t *testing.T) {
+	setup()
+	defer teardown()
+
+	dateFrom := time.Now()
+	dateTo := time.Now()
+	urlStr := fmt.Sprintf("/v1/rest/periods/%v/%v/%v/transactions.xml", testingToken, fmtDate(dateFrom), fmtDate(dateTo))
+
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, transactionsResponse)
+	})
+
+	opts := ByPeriodOptions{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+	stream, err := client.Transactions.StreamByPeriod(context.Background(), opts)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	want, err := parseTransactionsResponse(strings.NewReader(transactionsResponse), ParseOptions{})
+	require.NoError(t, err)
+
+	resp := &TransactionsResponse{Info: stream.Info}
+	for tx, err := range stream.All() {
+		require.NoError(t, err)
+		resp.Transactions = append(resp.Transactions, tx)
+	}
+
+	assertEqualTransactionsResp(t, want, resp)
+}
+
+func TestStreamByPeriodInvalidColumn(t *testing.T) {
+	setup()
+	defer teardown()
+
+	dateFrom := time.Now()
+	dateTo := time.Now()
+	urlStr := fmt.Sprintf("/v1/rest/periods/%v/%v/%v/transactions.xml", testingToken, fmtDate(dateFrom), fmtDate(dateTo))
+
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Replace(transactionsResponse, `id="27"`, `id="999"`, 1))
+	})
+
+	opts := ByPeriodOptions{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+	stream, err := client.Transactions.StreamByPeriod(context.Background(), opts)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var errs []error
+	for _, err := range stream.All() {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], `unable to parse column: "Reference plátce"`)
+}
+
+func TestStreamByPeriodUnexpectedRoot(t *testing.T) {
+	setup()
+	defer teardown()
+
+	dateFrom := time.Now()
+	dateTo := time.Now()
+	urlStr := fmt.Sprintf("/v1/rest/periods/%v/%v/%v/transactions.xml", testingToken, fmtDate(dateFrom), fmtDate(dateTo))
+
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, validationErrorResponse)
+	})
+
+	opts := ByPeriodOptions{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+	_, err := client.Transactions.StreamByPeriod(context.Background(), opts)
+
+	require.EqualError(t, err, `unexpected root element: "response"`)
+}
+
+const transactionsJSONResponse = `
+{
+  "accountStatement": {
+    "info": {
+      "accountId": "2501201133",
+      "bankId": "8330",
+      "currency": "EUR",
+      "iban": "SK2383300000002501201133",
+      "bic": "FIOZSKBAXXX",
+      "openingBalance": 0.00,
+      "closingBalance": 45.97,
+      "dateStart": "2017-01-01+0100",
+      "dateEnd": "2017-05-01+0200",
+      "yearList": null,
+      "idList": null,
+      "idFrom": 13926601410,
+      "idTo": 13926601410,
+      "idLastDownload": null
+    },
+    "transactionList": {
+      "transaction": [
+        {
+          "column22": {"value": 13926601410, "name": "ID pohybu", "id": 22},
+          "column0": {"value": "2017-04-11+0200", "name": "Datum", "id": 0},
+          "column1": {"value": 45.97, "name": "Objem", "id": 1},
+          "column14": {"value": "EUR", "name": "Měna", "id": 14},
+          "column2": {"value": "SK2183100000001100248431", "name": "Protiúčet", "id": 2},
+          "column10": {"value": "john doe", "name": "Název protiúčtu", "id": 10},
+          "column3": {"value": "2010", "name": "Kód banky", "id": 3},
+          "column12": {"value": "ZUNO BANK AG, pobočka zahraničnej banky", "name": "Název banky", "id": 12},
+          "column4": {"value": "0558", "name": "KS", "id": 4},
+          "column5": {"value": "0001", "name": "VS", "id": 5},
+          "column6": {"value": "0002", "name": "SS", "id": 6},
+          "column7": {"value": "john doe", "name": "Uživatelská identifikace", "id": 7},
+          "column16": {"value": "/DO2017-04-10/SPPrevod zo zuno, john doe", "name": "Zpráva pro příjemce", "id": 16},
+          "column8": {"value": "Bezhotovostní příjem", "name": "Typ", "id": 8},
+          "column9": {"value": "john doe", "name": "Provedl", "id": 9},
+          "column18": {"value": "45.97 EUR", "name": "Upřesnění", "id": 18},
+          "column25": {"value": "john doe", "name": "Komentář", "id": 25},
+          "column26": {"value": "RIDBSKBXXXX", "name": "BIC", "id": 26},
+          "column17": {"value": 15689512949, "name": "ID pokynu", "id": 17},
+          "column27": {"value": "2000000003", "name": "Reference plátce", "id": 27}
+        }
+      ]
+    }
+  }
+}
+`
+
+func TestByPeriodJSON(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.ResponseFormat = JSONFormat
+
+	dateFrom := time.Now()
+	dateTo := time.Now()
+	urlStr := fmt.Sprintf("/v1/rest/periods/%v/%v/%v/transactions.json", testingToken, fmtDate(dateFrom), fmtDate(dateTo))
+
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, transactionsJSONResponse)
+	})
+
+	opts := ByPeriodOptions{
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+	resp, err := client.Transactions.ByPeriod(context.Background(), opts)
+	require.NoError(t, err)
+
+	want, err := parseTransactionsResponse(strings.NewReader(transactionsResponse), ParseOptions{})
+	require.NoError(t, err)
+
+	assertEqualTransactionsResp(t, want, resp)
+}
+
+func TestLastStatement(t *testing.T) {
+	setup()
+	defer teardown()
+
+	urlStr := fmt.Sprintf("/v1/rest/lastStatement/%v/statement", testingToken)
+
+	mux.HandleFunc(urlStr, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, "2017,3\n")
+	})
+
+	resp, err := client.Transactions.LastStatement(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, &LastStatementResponse{Year: 2017, ID: 3}, resp)
+}
+
+func TestGetLastStatement(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/lastStatement/%v/statement", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "2017,3")
+	})
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/by-id/%v/2017/3/transactions.xml", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, transactionsResponse)
+	})
+
+	resp, err := client.Transactions.GetLastStatement(context.Background())
+	require.NoError(t, err)
+
+	want, err := parseTransactionsResponse(strings.NewReader(transactionsResponse), ParseOptions{})
+	require.NoError(t, err)
+
+	assertEqualTransactionsResp(t, want, resp)
+}
+
+func TestGetLastStatementDefaultLimiter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	require.Equal(t, DefaultRateLimitInterval, client.Limiter.Interval())
+
+	var statementCalled bool
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/lastStatement/%v/statement", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "2017,3")
+	})
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/by-id/%v/2017/3/transactions.xml", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		statementCalled = true
+	})
+
+	// the second request waits for the rate limit interval
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Transactions.GetLastStatement(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.False(t, statementCalled)
+}
+
+func chunkTransactionsResponse(opening string, closing string, ids ...int64) string {
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, `<AccountStatement><Info><accountId>2501201133</accountId><currency>EUR</currency>`+
+		`<openingBalance>%v</openingBalance><closingBalance>%v</closingBalance>`+
+		`<dateStart>2017-01-01+01:00</dateStart><dateEnd>2017-01-01+01:00</dateEnd>`, opening, closing)
+	if len(ids) > 0 {
+		fmt.Fprintf(buf, `<idFrom>%v</idFrom><idTo>%v</idTo>`, ids[0], ids[len(ids)-1])
+	}
+	buf.WriteString(`</Info><TransactionList>`)
+	for _, id := range ids {
+		fmt.Fprintf(buf, `<Transaction><column_22 name="ID pohybu" id="22">%v</column_22></Transaction>`, id)
+	}
+	buf.WriteString(`</TransactionList></AccountStatement>`)
+	return buf.String()
+}
+
+func TestByPeriodChunked(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = nil
+
+	chunks := map[string]string{
+		"2017-01-01/2017-01-10": chunkTransactionsResponse("0.00", "10.00", 1, 2),
+		"2017-01-11/2017-01-20": chunkTransactionsResponse("10.00", "10.00"),
+		"2017-01-21/2017-01-25": chunkTransactionsResponse("10.00", "25.00", 2, 3),
+	}
+
+	var requested []string
+	prefix := fmt.Sprintf("/v1/rest/periods/%v/", testingToken)
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		period := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), "/transactions.xml")
+		requested = append(requested, period)
+		fmt.Fprint(w, chunks[period])
+	})
+
+	opts := ByPeriodChunkedOptions{
+		DateFrom: time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+		DateTo:   time.Date(2017, time.January, 25, 0, 0, 0, 0, time.UTC),
+		Days:     10,
+	}
+	resp, err := client.Transactions.ByPeriodChunked(context.Background(), opts)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"2017-01-01/2017-01-10", "2017-01-11/2017-01-20", "2017-01-21/2017-01-25"}, requested)
+	require.Equal(t, decimal.RequireFromString("0.00"), resp.Info.OpeningBalance)
+	require.Equal(t, decimal.RequireFromString("25.00"), resp.Info.ClosingBalance)
+	require.Equal(t, int64(1), resp.Info.IDFrom)
+	require.Equal(t, int64(3), resp.Info.IDTo)
+
+	var ids []int64
+	for _, tx := range resp.Transactions {
+		ids = append(ids, tx.ID)
+	}
+	require.Equal(t, []int64{1, 2, 3}, ids)
+}
@@ -0,0 +1,198 @@
+package fio
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// rewindTimeout bounds moving the server side cursor back on shutdown.
+const rewindTimeout = 5 * time.Second
+
+// WatcherOptions represents options passed to NewWatcher.
+type WatcherOptions struct {
+	// Interval between two polls, it is never shorter than the client
+	// Limiter interval or DefaultRateLimitInterval when there is no Limiter.
+	Interval time.Duration
+
+	// Store persists id of the last emitted transaction, so transactions
+	// are not emitted again after restart. It is optional.
+	Store CursorStore
+
+	// OnPoll is called after every poll.
+	OnPoll func(stats PollStats)
+}
+
+// PollStats represents metrics of a single poll.
+type PollStats struct {
+	// Latency is the duration of the poll request.
+	Latency time.Duration
+
+	// SinceLastPoll is the time elapsed since the previous successful poll.
+	SinceLastPoll time.Duration
+
+	// Transactions is the number of emitted transactions.
+	Transactions int
+
+	// Err is the error which occurred during the poll.
+	Err error
+}
+
+// NewWatcher returns new watcher polling transactions using client.
+func NewWatcher(client *Client, opts WatcherOptions) *Watcher {
+	return &Watcher{
+		client: client,
+		opts:   opts,
+	}
+}
+
+// Watcher polls transactions since the last download and emits the new ones.
+// Transactions are de-duplicated by their ID, which grows with every transaction.
+// The server side last download cursor is moved back to the last emitted
+// transaction after a failed poll, as the server may have moved it already.
+// With Store the cursor is also moved back on start, so transactions not emitted
+// on shutdown are downloaded again by the next run. Without Store it is moved
+// back on shutdown within rewindTimeout, which fails when the Limiter does not
+// allow another request in time.
+type Watcher struct {
+	client *Client
+	opts   WatcherOptions
+
+	cursor   int64
+	lastPoll time.Time
+}
+
+// Run polls transactions until ctx is done and calls fn for every new transaction.
+// Errors of individual polls are reported to OnPoll and do not stop the watcher.
+func (w *Watcher) Run(ctx context.Context, fn func(tx Transaction)) error {
+	if err := w.load(ctx); err != nil {
+		return err
+	}
+
+	w.run(ctx, func(tx Transaction) bool {
+		fn(tx)
+		return true
+	})
+	return nil
+}
+
+// Watch polls transactions in the background until ctx is done and sends
+// the new ones to the returned channel, which is closed on shutdown.
+func (w *Watcher) Watch(ctx context.Context) (<-chan Transaction, error) {
+	if err := w.load(ctx); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Transaction)
+	go func() {
+		defer close(ch)
+		w.run(ctx, func(tx Transaction) bool {
+			select {
+			case ch <- tx:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+	return ch, nil
+}
+
+func (w *Watcher) load(ctx context.Context) error {
+	if w.opts.Store == nil {
+		return nil
+	}
+
+	cursor, err := w.opts.Store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	w.cursor = cursor
+	if cursor == 0 {
+		return nil
+	}
+	return w.setServerCursor(ctx, cursor)
+}
+
+func (w *Watcher) setServerCursor(ctx context.Context, id int64) error {
+	return w.client.Transactions.SetLastDownloadID(ctx, SetLastDownloadIDOptions{ID: int(id)})
+}
+
+func (w *Watcher) run(ctx context.Context, emit func(tx Transaction) bool) {
+	interval := w.interval()
+	for {
+		w.poll(ctx, emit)
+		if err := sleep(ctx, interval); err != nil {
+			return
+		}
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context, emit func(tx Transaction) bool) {
+	start := time.Now()
+	resp, err := w.client.Transactions.SinceLastDownload(ctx)
+
+	stats := PollStats{
+		Latency: time.Since(start),
+		Err:     err,
+	}
+	if !w.lastPoll.IsZero() {
+		stats.SinceLastPoll = start.Sub(w.lastPoll)
+	}
+
+	// the server may have moved its cursor even though the response was lost
+	if err != nil && w.cursor != 0 && ctx.Err() == nil {
+		if rerr := w.setServerCursor(ctx, w.cursor); rerr != nil {
+			stats.Err = errors.Join(err, rerr)
+		}
+	}
+
+	if err == nil {
+		w.lastPoll = start
+		cursor := w.cursor
+		stopped := false
+		for _, tx := range resp.Transactions {
+			if tx.ID <= w.cursor {
+				continue
+			}
+			if !emit(tx) {
+				stopped = true
+				break
+			}
+			w.cursor = tx.ID
+			stats.Transactions++
+		}
+
+		// the context may be already done, the cursor must be stored anyway
+		if w.opts.Store != nil && w.cursor != cursor {
+			stats.Err = w.opts.Store.Save(context.WithoutCancel(ctx), w.cursor)
+		}
+		// with Store the server side cursor is moved back by the next start
+		if stopped && w.opts.Store == nil {
+			rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rewindTimeout)
+			stats.Err = errors.Join(stats.Err, w.rewind(rctx, resp.Info.IDLastDownload))
+			cancel()
+		}
+	}
+
+	if w.opts.OnPoll != nil {
+		w.opts.OnPoll(stats)
+	}
+}
+
+// rewind moves the server side cursor back to the last emitted transaction,
+// or to the start of the download when nothing has been emitted yet.
+func (w *Watcher) rewind(ctx context.Context, lastDownload int64) error {
+	id := w.cursor
+	if id == 0 {
+		id = lastDownload
+	}
+	if id == 0 {
+		return nil
+	}
+	return w.setServerCursor(ctx, id)
+}
+
+func (w *Watcher) interval() time.Duration {
+	return max(w.opts.Interval, w.client.rateLimitInterval())
+}
@@ -0,0 +1,243 @@
+package fio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcherWatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = NewRateLimiter(time.Millisecond)
+
+	cursor := int64(10)
+	setupLastDownload(&cursor, []int64{10, 11, 12})
+
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor")}
+	require.NoError(t, store.Save(context.Background(), 11))
+
+	var mu sync.Mutex
+	var stats []PollStats
+	watcher := NewWatcher(client, WatcherOptions{
+		Store: store,
+		OnPoll: func(s PollStats) {
+			mu.Lock()
+			defer mu.Unlock()
+			stats = append(stats, s)
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := watcher.Watch(ctx)
+	require.NoError(t, err)
+
+	tx := <-ch
+	require.Equal(t, int64(12), tx.ID)
+
+	cancel()
+	for range ch {
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, stats)
+	require.NoError(t, stats[0].Err)
+	require.Equal(t, 1, stats[0].Transactions)
+
+	stored, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(12), stored)
+}
+
+func TestWatcherWatchCancelledMidBatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = NewRateLimiter(time.Millisecond)
+
+	cursor := int64(10)
+	setupLastDownload(&cursor, []int64{10, 11, 12, 13})
+
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor")}
+	require.NoError(t, store.Save(context.Background(), 10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := NewWatcher(client, WatcherOptions{Store: store}).Watch(ctx)
+	require.NoError(t, err)
+
+	tx := <-ch
+	require.Equal(t, int64(11), tx.ID)
+	ids := []int64{tx.ID}
+
+	// shutdown while the watcher is blocked sending the rest of the batch
+	cancel()
+	for tx := range ch {
+		ids = append(ids, tx.ID)
+	}
+	require.Less(t, len(ids), 3)
+
+	// the server side cursor is moved back by the next start
+	require.Equal(t, int64(13), cursor)
+
+	// the next run delivers the remaining transactions
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = NewWatcher(client, WatcherOptions{Store: store}).Run(ctx, func(tx Transaction) {
+		ids = append(ids, tx.ID)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{11, 12, 13}, ids)
+}
+
+func TestWatcherWatchCancelledMidBatchWithoutStore(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = NewRateLimiter(time.Millisecond)
+
+	cursor := int64(10)
+	setupLastDownload(&cursor, []int64{10, 11, 12, 13})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := NewWatcher(client, WatcherOptions{}).Watch(ctx)
+	require.NoError(t, err)
+
+	tx := <-ch
+	require.Equal(t, int64(11), tx.ID)
+	ids := []int64{tx.ID}
+
+	cancel()
+	for tx := range ch {
+		ids = append(ids, tx.ID)
+	}
+	require.Less(t, len(ids), 3)
+	require.Equal(t, ids[len(ids)-1], cursor)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = NewWatcher(client, WatcherOptions{}).Run(ctx, func(tx Transaction) {
+		ids = append(ids, tx.ID)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{11, 12, 13}, ids)
+}
+
+func TestWatcherPollErrorRewinds(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = NewRateLimiter(time.Millisecond)
+
+	var mu sync.Mutex
+	cursor := int64(10)
+	failed := false
+	mux.HandleFunc(fmt.Sprintf("/v1/rest/set-last-id/%v/", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		idStr := strings.Trim(strings.TrimPrefix(r.URL.Path, fmt.Sprintf("/v1/rest/set-last-id/%v/", testingToken)), "/")
+		cursor, _ = strconv.ParseInt(idStr, 10, 64)
+	})
+	mux.HandleFunc(fmt.Sprintf("/ib_api/rest/last/%v/transactions.xml", testingToken), func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var pending []int64
+		for _, id := range []int64{10, 11, 12} {
+			if id > cursor {
+				pending = append(pending, id)
+			}
+		}
+		if len(pending) > 0 {
+			cursor = pending[len(pending)-1]
+		}
+		// the first response is lost after the server moved its cursor
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, chunkTransactionsResponse("0.00", "0.00", pending...))
+	})
+
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor")}
+	require.NoError(t, store.Save(context.Background(), 10))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var errs []error
+	var ids []int64
+	watcher := NewWatcher(client, WatcherOptions{
+		Interval: 5 * time.Millisecond,
+		Store:    store,
+		OnPoll: func(s PollStats) {
+			errs = append(errs, s.Err)
+		},
+	})
+	err := watcher.Run(ctx, func(tx Transaction) {
+		ids = append(ids, tx.ID)
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, errs)
+	require.Error(t, errs[0])
+	require.Equal(t, []int64{11, 12}, ids)
+}
+
+func TestWatcherRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	client.Limiter = NewRateLimiter(time.Millisecond)
+
+	cursor := int64(0)
+	setupLastDownload(&cursor, []int64{1, 2})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var polls int
+	var ids []int64
+	watcher := NewWatcher(client, WatcherOptions{
+		Interval: 5 * time.Millisecond,
+		OnPoll: func(s PollStats) {
+			polls++
+			if polls > 1 {
+				require.Positive(t, s.SinceLastPoll)
+			}
+		},
+	})
+	err := watcher.Run(ctx, func(tx Transaction) {
+		ids = append(ids, tx.ID)
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, ids)
+	require.Greater(t, polls, 1)
+}
+
+func TestWatcherInterval(t *testing.T) {
+	c := NewClient(testingToken, nil)
+	require.Equal(t, DefaultRateLimitInterval, NewWatcher(c, WatcherOptions{Interval: time.Second}).interval())
+	require.Equal(t, time.Hour, NewWatcher(c, WatcherOptions{Interval: time.Hour}).interval())
+
+	c.Limiter = nil
+	require.Equal(t, DefaultRateLimitInterval, NewWatcher(c, WatcherOptions{}).interval())
+}